@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/chrisyxlee/snippets/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCacheDir string
+	flagOffline  bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&flagCacheDir, "cache-dir", cache.DefaultDir(), "directory that GitHub API responses are cached in")
+	rootCmd.Flags().BoolVar(&flagOffline, "offline", false, "generate the report purely from cached GitHub API responses")
+
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the GitHub API response cache",
+	Long: `Manage the on-disk cache of GitHub API responses. Cached responses are
+revalidated with conditional requests, which don't count against the rate limit.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how much is cached",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stats, err := cache.ReadStats(flagCacheDir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("path:      %s\n", flagCacheDir)
+		fmt.Printf("responses: %d\n", stats.Entries)
+		fmt.Printf("size:      %s\n", fmtBytes(stats.Bytes))
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached response",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cache.Clear(flagCacheDir)
+	},
+}
+
+func fmtBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
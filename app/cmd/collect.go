@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/page"
+	"github.com/chrisyxlee/snippets/internal/pulls"
+	"github.com/chrisyxlee/snippets/internal/search"
+	"github.com/chrisyxlee/snippets/internal/util"
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+)
+
+// collector gathers a user's GitHub activity within the report window.
+type collector struct {
+	ctx      context.Context
+	client   *github.Client
+	username string
+	// Search qualifiers limiting which repositories are searched.
+	scope     string
+	startTime time.Time
+	endTime   time.Time
+}
+
+// within returns true if the time is within the report window.
+func (c *collector) within(t time.Time) bool {
+	return util.InTimeRange(t, c.startTime, c.endTime)
+}
+
+// isUser returns true if the GitHub user is the user that the report is for.
+func (c *collector) isUser(user *github.User) bool {
+	return strings.EqualFold(user.GetLogin(), c.username)
+}
+
+// newGitHubIssue adds details about the issue that search results don't include.
+func (c *collector) newGitHubIssue(issue *github.Issue) *format.GitHubIssue {
+	return c.addDetails(&format.GitHubIssue{
+		Issue: issue,
+	})
+}
+
+// addDetails fills in the repository, which search results don't include.
+func (c *collector) addDetails(ghi *format.GitHubIssue) *format.GitHubIssue {
+	issue := ghi.Issue
+	owner, repo, err := getOwnerAndRepository(issue)
+	if err != nil {
+		internal.Log().Err(err).
+			Str("url", issue.GetURL()).
+			Msg("get owner and repository")
+		return ghi
+	}
+	ghi.Repository = fmt.Sprintf("%s/%s", owner, repo)
+
+	return ghi
+}
+
+// addPullRequestDetails fills in the merged state and other pull request details
+// for all of the pull requests at once through the GraphQL API. Any pull requests
+// that GraphQL couldn't answer for fall back to checking the merged state through
+// the REST API, one at a time.
+func (c *collector) addPullRequestDetails(ghis []*format.GitHubIssue) {
+	refs := make(map[pulls.Ref]*format.GitHubIssue)
+	for _, ghi := range ghis {
+		if !ghi.Issue.IsPullRequest() {
+			continue
+		}
+
+		owner, repo, ok := strings.Cut(ghi.Repository, "/")
+		if !ok {
+			continue
+		}
+		refs[pulls.Ref{Owner: owner, Repo: repo, Number: ghi.Issue.GetNumber()}] = ghi
+	}
+
+	keys := lo.Keys(refs)
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	details, err := pulls.Fetch(c.ctx, c.client, keys)
+	if err != nil {
+		internal.Log().Err(err).Msg("fetch pull request details, falling back to the REST API")
+	}
+
+	for _, ref := range keys {
+		ghi := refs[ref]
+		if d, ok := details[ref]; ok {
+			ghi.Merged = d.Merged
+			ghi.MergedAt = d.MergedAt
+			ghi.MergeCommitSHA = d.MergeCommitSHA
+			ghi.ReviewDecision = d.ReviewDecision
+			ghi.Additions = d.Additions
+			ghi.Deletions = d.Deletions
+			ghi.Labels = d.Labels
+			continue
+		}
+
+		isMerged, _, err := c.client.PullRequests.IsMerged(c.ctx, ref.Owner, ref.Repo, ref.Number)
+		if err != nil {
+			internal.Log().Err(err).Msg("check pull request is merged")
+			isMerged = false
+		}
+		ghi.Merged = isMerged
+	}
+}
+
+// searchIssues searches for issues matching the query whose date field is within
+// the report window, limited to the repositories in scope.
+func (c *collector) searchIssues(query string, field string) ([]*github.Issue, error) {
+	if len(c.scope) > 0 {
+		query = query + " " + c.scope
+	}
+
+	return search.Issues(c.ctx, c.client.Search, query, field, c.startTime, c.endTime)
+}
+
+// activity is everything collected for the user within the report window.
+type activity struct {
+	// Issues and pull requests that the user created or updated, keyed by their
+	// API URL.
+	authored map[string]*format.GitHubIssue
+	// Pull requests by other authors that the user reviewed.
+	reviewed []*format.GitHubIssue
+	// Issues and pull requests by other authors that the user only commented on.
+	discussed []*format.GitHubIssue
+}
+
+// all returns every item, with the authored items in the order of their keys.
+func (a *activity) all() []*format.GitHubIssue {
+	keys := lo.Keys(a.authored)
+	sort.Strings(keys)
+
+	out := lo.Map(keys, func(k string, _ int) *format.GitHubIssue {
+		return a.authored[k]
+	})
+	out = append(out, a.reviewed...)
+	return append(out, a.discussed...)
+}
+
+// totals counts the authored pull requests merged and issues closed within
+// [start, end), and the pull requests reviewed.
+func (a *activity) totals(start time.Time, end time.Time) format.Totals {
+	var t format.Totals
+	for _, ghi := range a.authored {
+		issue := ghi.Issue
+		switch {
+		case issue.IsPullRequest() && ghi.Merged:
+			// Pull requests checked through the REST API don't have the merge time,
+			// but are closed when they're merged.
+			mergedAt := issue.GetClosedAt().Time
+			if ghi.MergedAt != nil {
+				mergedAt = *ghi.MergedAt
+			}
+			if util.InTimeRange(mergedAt, start, end) {
+				t.PullRequestsMerged++
+			}
+		case !issue.IsPullRequest() && issue.GetState() == "closed":
+			if util.InTimeRange(issue.GetClosedAt().Time, start, end) {
+				t.IssuesClosed++
+			}
+		}
+	}
+	t.ReviewsGiven = len(a.reviewed)
+
+	return t
+}
+
+// collect gathers all of the user's activity within the report window.
+func (c *collector) collect() (*activity, error) {
+	authored, err := c.authored()
+	if err != nil {
+		return nil, err
+	}
+
+	commented, err := c.searchIssues(fmt.Sprintf("commenter:%s", c.username), "updated")
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments are attached to authored issues as well, since they show what the
+	// user has been updating.
+	var others []*github.Issue
+	for _, issue := range commented {
+		if ghi, ok := authored[issue.GetURL()]; ok {
+			if err = c.addComments(ghi); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		others = append(others, issue)
+	}
+
+	reviewed, err := c.reviewed(others)
+	if err != nil {
+		return nil, err
+	}
+
+	var discussed []*format.GitHubIssue
+	for _, issue := range others {
+		ghi, ok := lo.Find(reviewed, func(ghi *format.GitHubIssue) bool {
+			return ghi.Issue.GetURL() == issue.GetURL()
+		})
+		if !ok {
+			ghi = &format.GitHubIssue{Issue: issue}
+		}
+
+		if err = c.addComments(ghi); err != nil {
+			return nil, err
+		}
+
+		// Reviewed pull requests have their own section, and comments from before
+		// the report window don't count.
+		if !ok && ghi.Comments > 0 {
+			discussed = append(discussed, c.addDetails(ghi))
+		}
+	}
+
+	c.addPullRequestDetails(append(append(lo.Values(authored), reviewed...), discussed...))
+
+	return &activity{
+		authored:  authored,
+		reviewed:  reviewed,
+		discussed: discussed,
+	}, nil
+}
+
+// authored collects the issues and pull requests that the user created or
+// updated, keyed by their API URL.
+func (c *collector) authored() (map[string]*format.GitHubIssue, error) {
+	ghIssues := make(map[string]*format.GitHubIssue)
+	for _, field := range []string{"created", "updated"} {
+		issues, err := c.searchIssues(fmt.Sprintf("author:%s", c.username), field)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			if _, ok := ghIssues[issue.GetURL()]; !ok {
+				ghIssues[issue.GetURL()] = c.newGitHubIssue(issue)
+			}
+		}
+	}
+
+	return ghIssues, nil
+}
+
+// reviewed collects the pull requests by other authors that the user reviewed
+// within the report window. Pull requests that the user commented on are checked
+// for reviews as well, since the search doesn't always find those.
+func (c *collector) reviewed(commented []*github.Issue) ([]*format.GitHubIssue, error) {
+	prs, err := c.searchIssues(fmt.Sprintf("is:pr reviewed-by:%s -author:%s", c.username, c.username), "updated")
+	if err != nil {
+		return nil, err
+	}
+
+	prs = append(prs, lo.Filter(commented, func(issue *github.Issue, _ int) bool {
+		return issue.IsPullRequest()
+	})...)
+	prs = lo.UniqBy(prs, func(issue *github.Issue) string {
+		return issue.GetURL()
+	})
+
+	var reviewed []*format.GitHubIssue
+	for _, pr := range prs {
+		review, err := c.review(pr)
+		if err != nil {
+			return nil, err
+		}
+
+		// Commenting on the conversation isn't a review.
+		if review == nil {
+			continue
+		}
+
+		ghi := c.newGitHubIssue(pr)
+		ghi.Review = review
+		reviewed = append(reviewed, ghi)
+	}
+
+	return reviewed, nil
+}
+
+// addComments counts the comments that the user left on the issue within the
+// report window, and remembers the first one.
+func (c *collector) addComments(ghi *format.GitHubIssue) error {
+	owner, repo, err := getOwnerAndRepository(ghi.Issue)
+	if err != nil {
+		return err
+	}
+
+	var comments []*github.IssueComment
+	err = page.Paginate(fmt.Sprintf("comments for %s/%s#%d", owner, repo, ghi.Issue.GetNumber()), 100,
+		func(listOptions github.ListOptions) (page.Details, *github.Response, error) {
+			since := c.startTime
+			res, resp, err := c.client.Issues.ListComments(c.ctx, owner, repo, ghi.Issue.GetNumber(), &github.IssueListCommentsOptions{
+				Sort:        github.String("created"),
+				Direction:   github.String("asc"),
+				Since:       &since,
+				ListOptions: listOptions,
+			})
+			if err != nil {
+				return page.Details{}, resp, err
+			}
+
+			comments = append(comments, lo.Filter(res, func(comment *github.IssueComment, _ int) bool {
+				return c.isUser(comment.GetUser()) && c.within(comment.GetCreatedAt().Time)
+			})...)
+			return page.Details{}, resp, nil
+		})
+	if err != nil {
+		return fmt.Errorf("list comments for %s/%s#%d: %w", owner, repo, ghi.Issue.GetNumber(), err)
+	}
+
+	ghi.Comments = len(comments)
+	if len(comments) > 0 {
+		ghi.FirstCommentURL = comments[0].GetHTMLURL()
+	}
+
+	return nil
+}
+
+// review summarizes the reviews that the user submitted on the pull request within
+// the report window, or returns nil if there were none.
+func (c *collector) review(pr *github.Issue) (*format.Review, error) {
+	owner, repo, err := getOwnerAndRepository(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []*github.PullRequestReview
+	err = page.Paginate(fmt.Sprintf("reviews for %s/%s#%d", owner, repo, pr.GetNumber()), 100,
+		func(listOptions github.ListOptions) (page.Details, *github.Response, error) {
+			res, resp, err := c.client.PullRequests.ListReviews(c.ctx, owner, repo, pr.GetNumber(), &listOptions)
+			if err != nil {
+				return page.Details{}, resp, err
+			}
+
+			reviews = append(reviews, lo.Filter(res, func(review *github.PullRequestReview, _ int) bool {
+				return c.isUser(review.GetUser()) && c.within(review.GetSubmittedAt().Time)
+			})...)
+			return page.Details{}, resp, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("list reviews for %s/%s#%d: %w", owner, repo, pr.GetNumber(), err)
+	}
+
+	if len(reviews) == 0 {
+		return nil, nil
+	}
+
+	var comments int
+	err = page.Paginate(fmt.Sprintf("review comments for %s/%s#%d", owner, repo, pr.GetNumber()), 100,
+		func(listOptions github.ListOptions) (page.Details, *github.Response, error) {
+			res, resp, err := c.client.PullRequests.ListComments(c.ctx, owner, repo, pr.GetNumber(), &github.PullRequestListCommentsOptions{
+				Since:       c.startTime,
+				ListOptions: listOptions,
+			})
+			if err != nil {
+				return page.Details{}, resp, err
+			}
+
+			comments += lo.CountBy(res, func(comment *github.PullRequestComment) bool {
+				return c.isUser(comment.GetUser()) && c.within(comment.GetCreatedAt().Time)
+			})
+			return page.Details{}, resp, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("list review comments for %s/%s#%d: %w", owner, repo, pr.GetNumber(), err)
+	}
+
+	return format.NewReview(reviews, comments), nil
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/config"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/rules"
+	"github.com/chrisyxlee/snippets/internal/search"
+	"github.com/chrisyxlee/snippets/internal/teams"
+	"github.com/chrisyxlee/snippets/internal/util"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	flagConfig  string
+	flagProfile string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&flagConfig, "config", config.DefaultPath(), "path to the configuration file")
+	rootCmd.Flags().StringVar(&flagProfile, "profile", "",
+		"name of the configuration profile to use (default is the config's default_profile)")
+
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the configuration file",
+	Long:  `Manage the configuration file, which holds named profiles of defaults for generating a report.`,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file for mistakes",
+	Long:  `Check the configuration file for unknown keys and invalid values.`,
+	Args:  cobra.NoArgs,
+	// The problems are the output, so usage would only bury them.
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(flagConfig)
+		if err != nil {
+			return err
+		}
+
+		errs := config.Validate(data)
+		if c, err := config.Parse(data); err == nil {
+			errs = append(errs, validateConfig(c)...)
+		}
+
+		for _, err := range errs {
+			fmt.Printf("%s: %s\n", flagConfig, err)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%s has %d problems", flagConfig, len(errs))
+		}
+
+		fmt.Printf("%s: ok\n", flagConfig)
+		return nil
+	},
+}
+
+// validateConfig checks the values in the configuration the same way that flags
+// are checked.
+func validateConfig(c *config.Config) []error {
+	var errs []error
+	if len(c.DefaultProfile) > 0 {
+		if _, err := c.Profile(c.DefaultProfile); err != nil {
+			errs = append(errs, fmt.Errorf("default_profile: %w", err))
+		}
+	}
+
+	names := lo.Keys(c.Profiles)
+	sort.Strings(names)
+	for _, name := range names {
+		p := c.Profiles[name]
+		if p == nil {
+			continue
+		}
+
+		prefix := fmt.Sprintf("profiles.%s", name)
+		if err := p.Check(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+		if len(p.Period) > 0 {
+			if _, err := util.ParsePeriod(p.Period); err != nil {
+				if _, _, _, relErr := util.ParseRelativeWindow(p.Period, clk.Now()); relErr != nil {
+					errs = append(errs, fmt.Errorf("%s.period: %w", prefix, err))
+				}
+			}
+		}
+		if len(p.OutputFormat) > 0 && !lo.Contains(outputFormats, p.OutputFormat) {
+			errs = append(errs, fmt.Errorf("%s.output_format: unknown format `%s`, expected one of %s",
+				prefix, p.OutputFormat, strings.Join(outputFormats, ", ")))
+		}
+		if _, err := search.ScopeQualifiers(p.Repos, p.Orgs, p.ExcludeRepos); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+		if len(p.GitHubTeam) > 0 {
+			if _, _, err := teams.Parse(p.GitHubTeam); err != nil {
+				errs = append(errs, fmt.Errorf("%s.github_team: %w", prefix, err))
+			}
+		}
+		if err := teams.ValidateExclude(p.TeamExclude); err != nil {
+			errs = append(errs, fmt.Errorf("%s.team_exclude: %w", prefix, err))
+		}
+		if len(p.Template) > 0 {
+			if _, err := format.LoadTemplate(p.Template); err != nil {
+				errs = append(errs, fmt.Errorf("%s.template: %w", prefix, err))
+			}
+		}
+		rs := rules.Default()
+		if len(p.Rules) > 0 {
+			var err error
+			if rs, err = rules.Load(p.Rules); err != nil {
+				errs = append(errs, fmt.Errorf("%s.rules: %w", prefix, err))
+				continue
+			}
+		}
+		sectionTitles := append(rs.SectionTitles(), sectionOtherPullRequests, sectionDirectCommits, sectionReleased, sectionMessages)
+		for _, title := range p.Sections {
+			if !lo.ContainsBy(sectionTitles, func(t string) bool { return strings.EqualFold(t, title) }) {
+				errs = append(errs, fmt.Errorf("%s.sections: unknown section `%s`, expected one of [%s]",
+					prefix, title, strings.Join(sectionTitles, ", ")))
+			}
+		}
+	}
+
+	return errs
+}
+
+// loadProfile reads the profile selected by --profile, or the default profile if
+// none was selected. A missing configuration file is only ignored at the default
+// path, and only if no profile was selected.
+func loadProfile() (*config.Profile, error) {
+	c, err := config.Load(flagConfig)
+	if errors.Is(err, os.ErrNotExist) && flagConfig == config.DefaultPath() && len(flagProfile) == 0 {
+		internal.Log().Debug().Str("path", flagConfig).Msg("no configuration file")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load configuration: %w", err)
+	}
+
+	return c.Profile(flagProfile)
+}
+
+// applyProfile fills in the flags that weren't given on the command line from the
+// profile, so that flags always take precedence.
+func applyProfile(flags *pflag.FlagSet, p *config.Profile) error {
+	if p == nil {
+		return nil
+	}
+
+	set := func(name string, values ...string) error {
+		// Not every command that collects a report renders it too.
+		if flags.Lookup(name) == nil || flags.Changed(name) {
+			return nil
+		}
+
+		for _, value := range values {
+			if err := flags.Set(name, value); err != nil {
+				return fmt.Errorf("profile value for --%s: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	// Any of the window flags override the period from the profile, since they
+	// can't always be combined.
+	if len(p.Period) > 0 && !flags.Changed("since") && !flags.Changed("until") {
+		if err := set("period", p.Period); err != nil {
+			return err
+		}
+	}
+
+	if len(p.Host) > 0 {
+		if err := set("host", p.Host); err != nil {
+			return err
+		}
+	}
+
+	// The profile reports on one of a user, a list of logins, or a GitHub team, and
+	// any of them on the command line replaces it.
+	if !flags.Changed("user") && !flags.Changed("users") && !flags.Changed("team") {
+		var err error
+		switch {
+		case len(p.User) > 0:
+			err = set("user", p.User)
+		case len(p.Team) > 0:
+			err = set("users", p.Team...)
+		case len(p.GitHubTeam) > 0:
+			err = set("team", p.GitHubTeam)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(p.OutputFormat) > 0 {
+		if err := set("output-format", p.OutputFormat); err != nil {
+			return err
+		}
+	}
+
+	if err := set("repo", p.Repos...); err != nil {
+		return err
+	}
+	if err := set("org", p.Orgs...); err != nil {
+		return err
+	}
+	if err := set("exclude-repo", p.ExcludeRepos...); err != nil {
+		return err
+	}
+
+	if len(p.Template) > 0 {
+		if err := set("template", p.Template); err != nil {
+			return err
+		}
+	}
+
+	if len(p.Rules) > 0 {
+		if err := set("rules", p.Rules); err != nil {
+			return err
+		}
+	}
+
+	if err := set("git-repo", p.GitRepos...); err != nil {
+		return err
+	}
+	if err := set("git-email", p.GitEmails...); err != nil {
+		return err
+	}
+
+	if len(p.SlackExport) > 0 {
+		if err := set("slack-export", p.SlackExport); err != nil {
+			return err
+		}
+	}
+	if len(p.SlackUser) > 0 {
+		if err := set("slack-user", p.SlackUser); err != nil {
+			return err
+		}
+	}
+	if len(p.SlackURL) > 0 {
+		if err := set("slack-url", p.SlackURL); err != nil {
+			return err
+		}
+	}
+
+	if p.Releases {
+		if err := set("releases", "true"); err != nil {
+			return err
+		}
+	}
+	if p.ReleasedSection {
+		if err := set("released-section", "true"); err != nil {
+			return err
+		}
+	}
+
+	if err := set("team-exclude", p.TeamExclude...); err != nil {
+		return err
+	}
+	if p.Rollup {
+		if err := set("rollup", "true"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chrisyxlee/snippets/internal/editor"
+	"github.com/spf13/cobra"
+)
+
+var flagEdit bool
+
+func init() {
+	rootCmd.Flags().BoolVar(&flagEdit, "edit", false,
+		"open the report in $VISUAL or $EDITOR to write a summary and prune items before it's written")
+}
+
+// summaryPrompt is placed at the top of Markdown reports being edited, and removed
+// again afterwards.
+const summaryPrompt = "<!-- Write a summary of the period here, and delete any items that don't belong in the report. This comment is removed. -->\n\n"
+
+// editReport opens the rendered report in the user's editor and returns the
+// result. Nothing is returned if the editor fails or the report is left
+// unchanged, so that a half-edited report is never written.
+func editReport(cmd *cobra.Command, command string, rendered string, outputFormat string) (string, error) {
+	e := &editor.Editor{
+		Command: command,
+		Stdin:   cmd.InOrStdin(),
+		Stdout:  cmd.OutOrStdout(),
+		Stderr:  cmd.ErrOrStderr(),
+	}
+
+	if outputFormat == outputFormatJSON {
+		edited, err := e.Edit(cmd.Context(), rendered, ".json")
+		if err != nil {
+			return "", fmt.Errorf("report discarded: %w", err)
+		}
+		if !json.Valid([]byte(edited)) {
+			return "", errors.New("report discarded: the edited report isn't valid JSON")
+		}
+		return strings.TrimRight(edited, "\n"), nil
+	}
+
+	edited, err := e.Edit(cmd.Context(), summaryPrompt+rendered, ".md")
+	if err != nil {
+		return "", fmt.Errorf("report discarded: %w", err)
+	}
+
+	edited = strings.Replace(edited, strings.TrimSpace(summaryPrompt), "", 1)
+	return strings.TrimRight(strings.TrimLeft(edited, "\n"), "\n"), nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/gitlog"
+	"github.com/google/go-github/v53/github"
+)
+
+// sectionDirectCommits holds the commits from --git-repo that didn't go through a
+// pull request in the report.
+const sectionDirectCommits = "Direct commits"
+
+// sectionOtherPullRequests holds the commits from --git-repo that mention a pull
+// request that isn't in the report, grouped by the pull request.
+const sectionOtherPullRequests = "Other pull requests"
+
+var (
+	flagGitRepos  []string
+	flagGitEmails []string
+)
+
+func init() {
+	rootCmd.Flags().StringArrayVar(&flagGitRepos, "git-repo", nil,
+		"include the user's commits from the local git repository at this path (repeatable)")
+	rootCmd.Flags().StringArrayVar(&flagGitEmails, "git-email", nil,
+		"author email of the user's commits in --git-repo (repeatable, default is each repository's user.email)")
+}
+
+// addCommits adds the user's commits from the --git-repo repositories within the
+// window. Commits are listed under the pull request that they mention, which gets
+// its own section if it isn't in the report, and the rest are direct commits.
+func addCommits(ctx context.Context, client *github.Client, report *format.Report) error {
+	pullRequests := map[string]*format.Item{}
+	for _, section := range report.Sections {
+		for _, item := range section.Items {
+			if item.Type == format.ItemTypePullRequest {
+				pullRequests[fmt.Sprintf("%s#%d", item.Repository, item.Number)] = item
+			}
+		}
+	}
+
+	var direct, others []*format.Item
+	for _, path := range flagGitRepos {
+		host, repo := gitlog.Remote(ctx, path)
+		if len(repo) == 0 {
+			return fmt.Errorf("--git-repo `%s`: origin is not a GitHub repository", path)
+		}
+
+		emails := flagGitEmails
+		if len(emails) == 0 {
+			var err error
+			if emails, err = gitlog.Emails(ctx, path); err != nil {
+				return fmt.Errorf("--git-repo `%s`: %w", path, err)
+			}
+			if len(emails) == 0 {
+				return fmt.Errorf("--git-repo `%s`: no user.email is configured, use --git-email", path)
+			}
+		}
+
+		commits, err := gitlog.Log(ctx, path, emails, report.Window.Start, report.Window.End)
+		if err != nil {
+			return fmt.Errorf("--git-repo `%s`: %w", path, err)
+		}
+		internal.Log().Debug().Str("path", path).Int("commits", len(commits)).Msg("read git log")
+
+		for _, c := range commits {
+			commit := &format.Commit{
+				SHA:        c.SHA,
+				Subject:    c.Subject,
+				AuthoredAt: c.AuthoredAt,
+				Additions:  c.Additions,
+				Deletions:  c.Deletions,
+				HTMLURL:    fmt.Sprintf("https://%s/%s/commit/%s", host, repo, c.SHA),
+				URL:        fmt.Sprintf("%srepos/%s/commits/%s", client.BaseURL, repo, c.SHA),
+			}
+
+			if c.PullRequest == 0 {
+				direct = append(direct, format.NewCommitItem(repo, commit))
+				continue
+			}
+
+			// Pull requests outside of the window aren't in the report, but the commits
+			// are still the user's work, so they're grouped by the pull request instead.
+			key := fmt.Sprintf("%s#%d", repo, c.PullRequest)
+			pr, ok := pullRequests[key]
+			if !ok {
+				pr = &format.Item{
+					Type:       format.ItemTypePullRequest,
+					Number:     c.PullRequest,
+					Repository: repo,
+					Title:      strings.TrimSuffix(c.Subject, fmt.Sprintf(" (#%d)", c.PullRequest)),
+					CreatedAt:  c.AuthoredAt,
+					Reactions:  map[string]int{},
+					HTMLURL:    fmt.Sprintf("https://%s/%s/pull/%d", host, repo, c.PullRequest),
+					URL:        fmt.Sprintf("%srepos/%s/pulls/%d", client.BaseURL, repo, c.PullRequest),
+					Labels:     []string{},
+				}
+				pullRequests[key] = pr
+				others = append(others, pr)
+			}
+			pr.Commits = append(pr.Commits, commit)
+		}
+	}
+
+	if len(others) > 0 {
+		report.Sections = append(report.Sections, &format.Section{
+			Title: sectionOtherPullRequests,
+			Items: others,
+		})
+	}
+	if len(direct) > 0 {
+		report.Sections = append(report.Sections, &format.Section{
+			Title: sectionDirectCommits,
+			Items: direct,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/cache"
+	"github.com/chrisyxlee/snippets/internal/ratelimit"
+	"github.com/chrisyxlee/snippets/internal/replay"
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+	"golang.org/x/oauth2"
+)
+
+const defaultHost = "github.com"
+
+var (
+	flagHost   string
+	flagAPIURL string
+	flagRecord string
+	flagReplay string
+)
+
+// recorder records the GitHub API interactions when --record is given.
+var recorder *replay.Recorder
+
+func init() {
+	rootCmd.Flags().StringVar(&flagHost, "host", defaultHost,
+		"GitHub host to report on, i.e. a GitHub Enterprise Server host like github.example.com")
+	rootCmd.Flags().StringVar(&flagAPIURL, "api-url", "",
+		"base URL of the GitHub REST API, i.e. a proxy or a fake server for testing (default is derived from --host)")
+
+	// These are for creating and using test fixtures.
+	rootCmd.Flags().StringVar(&flagRecord, "record", "", "record the GitHub API interactions into this fixture file")
+	rootCmd.Flags().StringVar(&flagReplay, "replay", "", "answer GitHub API requests from this fixture file")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	lo.Must0(rootCmd.Flags().MarkHidden("record"))
+	lo.Must0(rootCmd.Flags().MarkHidden("replay"))
+}
+
+// normalizeHost strips the scheme and any path from the host, so that both
+// "github.example.com" and "https://github.example.com/" are accepted. The API
+// host of github.com is github.com too, rather than an enterprise host.
+func normalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if u, err := url.Parse(host); err == nil && len(u.Host) > 0 {
+		host = u.Host
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "/"))
+	if host == "api."+defaultHost {
+		return defaultHost
+	}
+	return host
+}
+
+func isEnterpriseHost(host string) bool {
+	return host != defaultHost
+}
+
+func getGitHubToken(host string) (string, error) {
+	envVars := []string{"GITHUB_TOKEN", "GITHUB_OAUTH_TOKEN"}
+	if isEnterpriseHost(host) {
+		envVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+
+	for _, envVar := range envVars {
+		token, ok := os.LookupEnv(envVar)
+		if ok && len(token) > 0 {
+			internal.Log().Debug().Msgf("fetching token from %s", envVar)
+			return token, nil
+		}
+	}
+
+	if _, err := exec.LookPath("gh"); err == nil {
+		internal.Log().Debug().Msg("user has gh installed")
+		var b bytes.Buffer
+		ghAuthCmd := exec.Command("gh", "auth", "token", "--hostname", host)
+		ghAuthCmd.Stdout = &b
+		if err = ghAuthCmd.Run(); err == nil {
+			token := strings.Trim(b.String(), "\t\n ")
+			if len(token) > 0 {
+				return token, nil
+			}
+			// TODO: ask user for permission to use the token
+			internal.Log().Debug().Msg("user has gh installed and is logged in")
+		}
+	}
+
+	return "", fmt.Errorf("github token for %s must be provided through %s environment variables or through the gh CLI",
+		host, strings.Join(envVars, " or "))
+}
+
+// getBaseURL parses --api-url, or returns nil if it isn't given.
+func getBaseURL() (*url.URL, error) {
+	if len(flagAPIURL) == 0 {
+		return nil, nil
+	}
+
+	baseURL, err := url.Parse(strings.TrimSuffix(flagAPIURL, "/") + "/")
+	if err != nil || len(baseURL.Host) == 0 {
+		return nil, fmt.Errorf("--api-url `%s` must be an absolute URL", flagAPIURL)
+	}
+
+	return baseURL, nil
+}
+
+// getCacheDir is the directory that everything fetched from the host is cached in,
+// which is named after the host of --api-url instead when it's given.
+func getCacheDir(host string) string {
+	if baseURL, err := getBaseURL(); err == nil && baseURL != nil {
+		// Ports aren't allowed in directory names everywhere.
+		host = strings.ReplaceAll(baseURL.Host, ":", "_")
+	}
+
+	return filepath.Join(flagCacheDir, host)
+}
+
+// newGitHubClient creates a client for the host, which is either github.com or a
+// GitHub Enterprise Server host. Responses are cached on disk, unless they're being
+// recorded or replayed.
+func newGitHubClient(ctx context.Context, host string, token string) (*github.Client, error) {
+	baseURL, err := getBaseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{
+		AccessToken: token,
+	})
+	httpClient := oauth2.NewClient(ctx, tokenSource)
+
+	switch {
+	case len(flagReplay) > 0:
+		interactions, err := replay.Load(flagReplay)
+		if err != nil {
+			return nil, fmt.Errorf("load --replay fixture: %w", err)
+		}
+		httpClient = &http.Client{Transport: replay.NewReplayer(interactions)}
+	case len(flagRecord) > 0:
+		recorder = &replay.Recorder{Base: httpClient.Transport}
+		httpClient.Transport = recorder
+	default:
+		// The token is set before the cache sees the request, so that responses
+		// are never shared between tokens.
+		httpClient.Transport = &oauth2.Transport{
+			Source: tokenSource,
+			Base: &cache.Transport{
+				Dir:     getCacheDir(host),
+				Offline: flagOffline,
+			},
+		}
+	}
+	// Reports collected at the same time share the token's rate limit, so once one
+	// of them runs into it, the rest wait too.
+	httpClient.Transport = &ratelimit.Transport{Base: httpClient.Transport}
+
+	if baseURL != nil {
+		client := github.NewClient(httpClient)
+		client.BaseURL = baseURL
+		client.UploadURL = baseURL
+		return client, nil
+	}
+
+	if !isEnterpriseHost(host) {
+		return github.NewClient(httpClient), nil
+	}
+
+	client, err := github.NewEnterpriseClient(
+		fmt.Sprintf("https://%s/api/v3/", host),
+		fmt.Sprintf("https://%s/api/uploads/", host),
+		httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("create client for %s: %w", host, err)
+	}
+
+	return client, nil
+}
+
+// saveRecording writes the interactions to the --record fixture, if recording.
+func saveRecording() error {
+	if recorder == nil {
+		return nil
+	}
+
+	if err := recorder.Save(flagRecord); err != nil {
+		return err
+	}
+
+	internal.Log().Info().Str("path", flagRecord).Msg("recorded GitHub API interactions")
+	return nil
+}
+
+// getUsername resolves the user that the report is for. The --user flag takes
+// precedence, and otherwise the owner of the GitHub token is used.
+func getUsername(ctx context.Context, client *github.Client) (string, error) {
+	if len(flagUser) > 0 {
+		return flagUser, nil
+	}
+
+	var (
+		user *github.User
+		err  error
+	)
+	for {
+		user, _, err = client.Users.Get(ctx, "")
+		if !ratelimit.WaitIfRateLimited(err) {
+			break
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up the owner of the GitHub token (or set --user): %w", err)
+	}
+
+	if len(user.GetLogin()) == 0 {
+		return "", errors.New("the GitHub token has no user, set --user instead")
+	}
+
+	return user.GetLogin(), nil
+}
+
+// getOwnerAndRepository reads the owner and repository from the issue's API
+// repository URL, i.e. "https://api.github.com/repos/owner/name". Unlike the HTML
+// URL, this has the same shape on GitHub Enterprise Server.
+func getOwnerAndRepository(issue *github.Issue) (string, string, error) {
+	u, err := url.Parse(issue.GetRepositoryURL())
+	if err != nil {
+		return "", "", fmt.Errorf("parse repository url `%s`: %w", issue.GetRepositoryURL(), err)
+	}
+
+	_, fullName, ok := strings.Cut(u.Path, "/repos/")
+	if ok {
+		if owner, repo, ok := strings.Cut(strings.Trim(fullName, "/"), "/"); ok && len(owner) > 0 && len(repo) > 0 {
+			return owner, repo, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("no owner and repository in repository url `%s`", issue.GetRepositoryURL())
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/fakegithub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeGitHub starts a fake GitHub API seeded with a couple of weeks of work by
+// octocat, up to when the fixtures were recorded.
+func newFakeGitHub(t *testing.T) *fakegithub.Server {
+	s := fakegithub.New("octocat")
+	t.Cleanup(s.Close)
+
+	start := recordedAt.AddDate(0, 0, -14)
+	mergedAt := start.Add(50 * time.Hour)
+	s.AddIssues(
+		&fakegithub.Issue{
+			Repo:           "octo/widgets",
+			Number:         12,
+			Title:          "Add sprockets",
+			Author:         "octocat",
+			State:          "closed",
+			Labels:         []string{"feature"},
+			CreatedAt:      start.Add(time.Hour),
+			UpdatedAt:      mergedAt,
+			ClosedAt:       &mergedAt,
+			PullRequest:    true,
+			MergedAt:       &mergedAt,
+			MergeCommitSHA: "5c0ffee1d5e5a1b3c4d2e9f8a7b6c5d4e3f2a1b0",
+			ReviewDecision: "APPROVED",
+			Additions:      120,
+			Deletions:      4,
+		},
+		&fakegithub.Issue{
+			Repo:      "octo/widgets",
+			Number:    13,
+			Title:     "Sprockets are too loud",
+			Author:    "octocat",
+			CreatedAt: start.Add(2 * time.Hour),
+			UpdatedAt: start.Add(3 * time.Hour),
+			Reactions: map[string]int{"+1": 3},
+		},
+		&fakegithub.Issue{
+			Repo:        "octo/widgets",
+			Number:      40,
+			Title:       "Speed up widget rendering",
+			Author:      "hubot",
+			CreatedAt:   start.Add(24 * time.Hour),
+			UpdatedAt:   start.Add(72 * time.Hour),
+			PullRequest: true,
+			Reviews: []fakegithub.Review{
+				{ID: 1, Author: "octocat", State: "CHANGES_REQUESTED", SubmittedAt: start.Add(48 * time.Hour)},
+			},
+			ReviewComments: []fakegithub.Comment{
+				{ID: 2, Author: "octocat", CreatedAt: start.Add(48 * time.Hour)},
+			},
+		},
+		&fakegithub.Issue{
+			Repo:      "octo/gadgets",
+			Number:    41,
+			Title:     "Gadgets crash on startup",
+			Author:    "monalisa",
+			CreatedAt: start.AddDate(0, 0, -30),
+			UpdatedAt: start.Add(96 * time.Hour),
+			Comments: []fakegithub.Comment{
+				{ID: 3, Author: "octocat", CreatedAt: start.Add(96 * time.Hour)},
+			},
+		},
+	)
+
+	return s
+}
+
+func TestIntegrationReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	s := newFakeGitHub(t)
+	got, err := execute(t, "--api-url", s.URL, "--output-format", "markdown")
+	require.NoError(t, err)
+
+	assert.Contains(t, got, "# biweekly report for octocat: 2026-10-04")
+	assert.Contains(t, got, "- PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** <=3 days - Add sprockets\n")
+	assert.Contains(t, got, "- Issue [octo/widgets#13](https://github.com/octo/widgets/issues/13) **active** <=2 weeks - Sprockets are too loud (3 👍)\n")
+	assert.Contains(t, got, "## Reviewed\n\n- PR [octo/widgets#40](https://github.com/octo/widgets/pull/40) **active** <=2 weeks - Speed up widget rendering _changes requested, 1 review comment_\n")
+	assert.Contains(t, got, "## Discussed\n\n- Issue [octo/gadgets#41](https://github.com/octo/gadgets/issues/41) **active** <=2 months - Gadgets crash on startup _[1 comment](https://github.com/octo/gadgets/issues/41#issuecomment-3)_\n")
+}
+
+func TestIntegrationPeriod(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	s := newFakeGitHub(t)
+
+	// Early in the week, the week so far is only hours long.
+	monday := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.UTC)
+	got, err := executeAt(t, monday, "--api-url", s.URL, "--output-format", "markdown", "--period", "this-week")
+	require.NoError(t, err)
+	assert.Contains(t, got, "# weekly report for octocat: 2026-10-12\n")
+
+	// February is shorter than a month of 30 days.
+	march := time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC)
+	got, err = executeAt(t, march, "--api-url", s.URL, "--output-format", "markdown", "--period", "last-month")
+	require.NoError(t, err)
+	assert.Contains(t, got, "# monthly report for octocat: 2026-02-01\n")
+
+	dir := t.TempDir()
+	_, err = executeAt(t, march, "--api-url", s.URL, "--output-format", "markdown", "--period", "last-month", "--out-dir", dir)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "2026-02-01_2026-02-28-monthly.md"))
+}
+
+func TestIntegrationReleases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	s := newFakeGitHub(t)
+	start := recordedAt.AddDate(0, 0, -14)
+	s.AddReleases(
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.3.0", PublishedAt: start},
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.4.0", Name: "Widgets 1.4", PublishedAt: start.AddDate(0, 0, 5),
+			Commits: []string{"5c0ffee1d5e5a1b3c4d2e9f8a7b6c5d4e3f2a1b0"}},
+	)
+
+	got, err := execute(t, "--api-url", s.URL, "--output-format", "markdown", "--releases")
+	require.NoError(t, err)
+	assert.Contains(t, got, "- PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** <=3 days - Add sprockets "+
+		"_[Shipped in v1.4.0](https://github.com/octo/widgets/releases/tag/v1.4.0)_\n")
+	assert.NotContains(t, got, "## Released this cycle")
+
+	got, err = execute(t, "--api-url", s.URL, "--output-format", "markdown", "--released-section")
+	require.NoError(t, err)
+	assert.Contains(t, got, "## Released this cycle\n\n"+
+		"- Release [octo/widgets@v1.4.0](https://github.com/octo/widgets/releases/tag/v1.4.0) - Widgets 1.4\n")
+}
+
+// The report should come out the same when the API pushes back, since rate limits
+// are waited out and retried.
+func TestIntegrationRateLimited(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	unlimited := newFakeGitHub(t)
+	want, err := execute(t, "--api-url", unlimited.URL, "--output-format", "json")
+	require.NoError(t, err)
+
+	s := newFakeGitHub(t)
+	s.InjectRateLimit(2)
+	s.InjectAbuse(1, 0)
+	got, err := execute(t, "--api-url", s.URL, "--output-format", "json")
+	require.NoError(t, err)
+
+	// The API URLs differ between the servers.
+	assert.Equal(t, strings.ReplaceAll(want, unlimited.URL, ""), strings.ReplaceAll(got, s.URL, ""))
+	assert.Equal(t, unlimited.Requests()+3, s.Requests())
+}
+
+func TestIntegrationTeam(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	s := newFakeGitHub(t)
+	start := recordedAt.AddDate(0, 0, -14)
+	closedAt := start.Add(100 * time.Hour)
+	s.AddIssues(&fakegithub.Issue{
+		Repo:      "octo/widgets",
+		Number:    42,
+		Title:     "Widgets flicker",
+		Author:    "hubot",
+		State:     "closed",
+		CreatedAt: start.Add(5 * time.Hour),
+		UpdatedAt: closedAt,
+		ClosedAt:  &closedAt,
+	})
+
+	got, err := execute(t, "--api-url", s.URL, "--output-format", "markdown", "--users", "octocat,hubot", "--rollup")
+	require.NoError(t, err)
+	assert.Contains(t, got, "# biweekly team report: 2026-10-04\n\n"+
+		"For the period of 2026-10-04 to 2026-10-18 (2 weeks), the team of 2 merged 1 pull request, gave 1 review, and closed 1 issue.\n\n")
+	assert.Contains(t, got, "| octocat | 1 | 1 | 0 |\n| hubot | 0 | 0 | 1 |\n| **Team** | 1 | 1 | 1 |\n")
+	assert.Contains(t, got, "## octocat\n\n")
+	assert.Contains(t, got, "## hubot\n\n")
+	assert.Less(t, strings.Index(got, "## octocat"), strings.Index(got, "## hubot"))
+	assert.Contains(t, got, "- Issue [octo/widgets#42](https://github.com/octo/widgets/issues/42)")
+
+	dir := t.TempDir()
+	_, err = execute(t, "--api-url", s.URL, "--users", "octocat,hubot", "--out-dir", dir)
+	require.NoError(t, err)
+	for _, user := range []string{"octocat", "hubot"} {
+		report, err := os.ReadFile(filepath.Join(dir, user, "2026-10-04_2026-10-18-biweekly.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(report), "# biweekly report for "+user)
+	}
+
+	_, err = execute(t, "--api-url", s.URL, "--users", "octocat,hubot")
+	assert.EqualError(t, err, "--users writes a report for each person into --out-dir, or combine them with --rollup")
+}
+
+func TestIntegrationGitHubTeam(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	s := newFakeGitHub(t)
+	s.AddTeams(
+		&fakegithub.Team{Org: "octo", Slug: "eng", Members: []string{"octocat", "dependabot[bot]"}},
+		&fakegithub.Team{Org: "octo", Slug: "widgets", Parent: "eng", Members: []string{"hubot", "deploy-widgets"}},
+	)
+	cacheDir := t.TempDir()
+	args := []string{"--api-url", s.URL, "--cache-dir", cacheDir, "--output-format", "markdown",
+		"--team", "octo/eng", "--team-exclude", "deploy-*", "--rollup"}
+
+	got, err := execute(t, args...)
+	require.NoError(t, err)
+	assert.Contains(t, got, "# biweekly team report for octo/eng: 2026-10-04\n\n"+
+		"For the period of 2026-10-04 to 2026-10-18 (2 weeks), the team of 2 merged 1 pull request, gave 1 review, and closed 0 issues.\n\n")
+	assert.Contains(t, got, "| hubot | 0 | 0 | 0 |\n| octocat | 1 | 1 | 0 |\n")
+
+	// The roster is reused until it's refreshed.
+	s.AddTeams(&fakegithub.Team{Org: "octo", Slug: "gadgets", Parent: "eng", Members: []string{"monalisa"}})
+	got, err = execute(t, args...)
+	require.NoError(t, err)
+	assert.NotContains(t, got, "## monalisa")
+
+	got, err = execute(t, append(args, "--refresh-team")...)
+	require.NoError(t, err)
+	assert.Contains(t, got, "the team of 3")
+	assert.Contains(t, got, "## monalisa\n\n")
+
+	_, err = execute(t, "--api-url", s.URL, "--team", "octo", "--rollup")
+	assert.EqualError(t, err, "--team: team `octo` must be formatted as org/team-slug")
+}
+
+// Rerunning the report shortly after should be answered from the cache, even
+// though the window runs until now.
+func TestIntegrationOffline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+
+	s := newFakeGitHub(t)
+	cacheDir := t.TempDir()
+	want, err := executeAt(t, recordedAt.Add(-50*time.Minute), "--api-url", s.URL, "--cache-dir", cacheDir,
+		"--output-format", "markdown")
+	require.NoError(t, err)
+
+	got, err := executeAt(t, recordedAt.Add(-49*time.Minute), "--api-url", s.URL, "--cache-dir", cacheDir,
+		"--output-format", "markdown", "--offline")
+	require.NoError(t, err)
+	// Open items are a minute older, which doesn't change how their age reads.
+	assert.Equal(t, want, got)
+}
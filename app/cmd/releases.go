@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/releases"
+	"github.com/chrisyxlee/snippets/internal/util"
+	"github.com/google/go-github/v53/github"
+)
+
+// sectionReleased holds the releases published within the window that shipped
+// pull requests in the report.
+const sectionReleased = "Released this cycle"
+
+var (
+	flagReleases        bool
+	flagReleasedSection bool
+)
+
+func init() {
+	rootCmd.Flags().BoolVar(&flagReleases, "releases", false,
+		"show the first release that shipped each merged pull request")
+	rootCmd.Flags().BoolVar(&flagReleasedSection, "released-section", false,
+		fmt.Sprintf("add a %q section with the releases published within the window, implies --releases", sectionReleased))
+}
+
+// addReleases attributes each merged pull request in the report to the first
+// release that shipped it, and adds a section with the releases that were
+// published within the window if asked to. Pull requests that can't be attributed
+// are left as is, so that a missing tag doesn't fail the whole report.
+func addReleases(ctx context.Context, client *github.Client, report *format.Report) {
+	if !flagReleases && !flagReleasedSection {
+		return
+	}
+
+	finder := releases.NewFinder(ctx, client)
+	published := make(map[string]*format.Item)
+	for _, section := range report.Sections {
+		for _, item := range section.Items {
+			if item.Type != format.ItemTypePullRequest || item.MergedAt == nil || len(item.MergeCommitSHA) == 0 {
+				continue
+			}
+
+			release, err := finder.First(item.Repository, item.MergeCommitSHA, *item.MergedAt)
+			if err != nil {
+				internal.Log().Err(err).Str("url", item.HTMLURL).Msg("find release")
+				continue
+			}
+			if release == nil {
+				continue
+			}
+
+			item.Release = format.NewRelease(release)
+			if util.InTimeRange(item.Release.PublishedAt, report.Window.Start, report.Window.End) {
+				published[item.Release.HTMLURL] = format.NewReleaseItem(item.Repository, item.Release)
+			}
+		}
+	}
+
+	if !flagReleasedSection || len(published) == 0 {
+		return
+	}
+
+	items := make([]*format.Item, 0, len(published))
+	for _, item := range published {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+
+	report.Sections = append(report.Sections, &format.Section{
+		Title: sectionReleased,
+		Items: items,
+	})
+}
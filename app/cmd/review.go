@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/review"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var flagReviewDir string
+
+// Flags that only matter for rendering and writing the report, which review
+// doesn't do.
+var renderFlags = []string{"output-format", "template", "out", "out-dir", "force", "edit"}
+
+var shareFlagsOnce sync.Once
+
+// shareFlags adds the flags for collecting the report to review, which collects the
+// same report. It runs from Execute once every file has registered its flags, since
+// the order that files are initialized in isn't something to rely on.
+func shareFlags() {
+	shareFlagsOnce.Do(func() {
+		rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if !lo.Contains(renderFlags, f.Name) && !lo.Contains(teamFlags, f.Name) {
+				reviewCmd.Flags().AddFlag(f)
+			}
+		})
+	})
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&flagReviewDir, "review-dir", review.DefaultDir(),
+		"directory that the decisions made while reviewing reports are saved in")
+
+	rootCmd.AddCommand(reviewCmd)
+}
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Triage the items in the report before publishing it",
+	Long: `Collect the report and review its items in a full-screen interface: move items
+between sections, hide them, star highlights, and attach notes while previewing
+the rendered Markdown. Decisions are saved, and every report over the same window
+applies them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := resolveReportOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		report, err := opts.newReport(cmd)
+		if err != nil {
+			return err
+		}
+
+		path := opts.getReviewPath(report.User)
+		decisions, err := review.Load(path)
+		if err != nil {
+			return err
+		}
+
+		final, err := tea.NewProgram(review.NewModel(report, decisions),
+			tea.WithAltScreen(),
+			tea.WithInput(cmd.InOrStdin()),
+			tea.WithOutput(cmd.OutOrStdout()),
+		).Run()
+		if err != nil {
+			return fmt.Errorf("review: %w", err)
+		}
+
+		if !final.(*review.Model).Saved() {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Discarded the review.")
+			return nil
+		}
+
+		if err = decisions.Save(path); err != nil {
+			return fmt.Errorf("save review: %w", err)
+		}
+		internal.Log().Info().Str("path", path).Msg("saved review")
+		fmt.Fprintln(cmd.ErrOrStderr(), "Saved the review, run snippet over the same window to write the report.")
+		return nil
+	},
+}
+
+// getReviewPath is where the decisions for the user's report over the window are
+// saved. Rolling windows move with every run, so their decisions are saved under
+// the calendar period that the window ends in instead, i.e. the current week for
+// --period this-week, and carry over until the period is over.
+func (o *reportOptions) getReviewPath(user string) string {
+	start, end := o.startTime, o.endTime
+	if o.rolling && len(o.period) > 0 {
+		start = o.period.Truncate(end.Add(-time.Nanosecond))
+		end = o.period.AddTo(start, 1)
+	}
+
+	return review.Path(flagReviewDir, user, start, end)
+}
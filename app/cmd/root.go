@@ -1,97 +1,441 @@
 package cmd
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
-	"regexp"
+	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/config"
+	"github.com/chrisyxlee/snippets/internal/editor"
 	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/output"
+	"github.com/chrisyxlee/snippets/internal/review"
+	"github.com/chrisyxlee/snippets/internal/rules"
+	"github.com/chrisyxlee/snippets/internal/search"
+	"github.com/chrisyxlee/snippets/internal/util"
 	"github.com/google/go-github/v53/github"
+	"github.com/mattn/go-isatty"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
-	"golang.org/x/oauth2"
 )
 
+const (
+	outputFormatAuto     = "auto"
+	outputFormatText     = "text"
+	outputFormatMarkdown = "markdown"
+	outputFormatJSON     = "json"
+)
+
+var outputFormats = []string{outputFormatAuto, outputFormatText, outputFormatMarkdown, outputFormatJSON}
+
+var clk = clock.New()
+
 var (
-	reUsername        = regexp.MustCompile(`Logged in to .* (account|as) (.*) \(.*\)`)
-	reOwnerRepository = regexp.MustCompile(`https://github.com/(.*)/(.*)/(pull|issue)/\d+`)
+	flagSince        string
+	flagUntil        string
+	flagPeriod       string
+	flagOutputFormat string
+	flagOut          string
+	flagOutDir       string
+	flagForce        bool
+	flagRepos        []string
+	flagOrgs         []string
+	flagExcludeRepos []string
+	flagUser         string
+	flagTemplate     string
+	flagRules        string
+	flagExplain      bool
 )
 
+func init() {
+	rootCmd.Flags().StringVar(&flagSince, "since", "",
+		fmt.Sprintf("start of the report window, as %q, %q, or relative like \"2w\"", util.TimeLayout, util.ISODateLayout))
+	rootCmd.Flags().StringVar(&flagUntil, "until", "",
+		"end of the report window, in the same formats as --since (default now)")
+	rootCmd.Flags().StringVar(&flagPeriod, "period", string(util.PeriodBiweek),
+		fmt.Sprintf("length of the report window (%s), or a calendar window like \"last-week\" or \"this-month\"",
+			strings.Join(util.PeriodNames(), ", ")))
+	rootCmd.Flags().StringVar(&flagOutputFormat, "output-format", outputFormatAuto,
+		fmt.Sprintf("format of the report (%s), where auto is text for a terminal and markdown otherwise",
+			strings.Join(outputFormats, ", ")))
+	rootCmd.Flags().StringVar(&flagTemplate, "template", "",
+		fmt.Sprintf("render the report with a built-in template (%s) or the text/template file at this path",
+			strings.Join(format.TemplateNames(), ", ")))
+	rootCmd.Flags().StringVar(&flagRules, "rules", rules.DefaultName,
+		fmt.Sprintf("place items into sections with built-in rules (%s) or the rules in the YAML file at this path",
+			strings.Join(rules.Names(), ", ")))
+	rootCmd.Flags().BoolVar(&flagExplain, "explain", false,
+		"print which rule placed each item into its section to stderr")
+	rootCmd.Flags().StringVar(&flagOut, "out", "", "write the report to this file instead of stdout")
+	rootCmd.Flags().StringVar(&flagOutDir, "out-dir", "",
+		"write the report into this directory, named after the report window")
+	rootCmd.Flags().BoolVar(&flagForce, "force", false, "overwrite the report file if it already exists")
+	rootCmd.MarkFlagsMutuallyExclusive("out", "out-dir")
+	rootCmd.Flags().StringVar(&flagUser, "user", "",
+		"GitHub login of the user to report on (default is the owner of the GitHub token)")
+	rootCmd.Flags().StringArrayVar(&flagRepos, "repo", nil,
+		"only include this repository, formatted as owner/name (repeatable)")
+	rootCmd.Flags().StringArrayVar(&flagOrgs, "org", nil, "only include repositories in this organization (repeatable)")
+	rootCmd.Flags().StringArrayVar(&flagExcludeRepos, "exclude-repo", nil,
+		"leave out this repository, formatted as owner/name (repeatable)")
+}
+
 // TODO: create a pie chart for how long you spent on each issue? (length of comment / most number of comments in this cycle) -- gantt??
 // don't want this to be a slippery slope into MTTR lol
 
 // TODO: view report (give directory, use glamour?)
 
-func getGitHubToken() (string, error) {
-	githubToken, ok := os.LookupEnv("GITHUB_TOKEN")
-	if ok && len(githubToken) > 0 {
-		internal.Log().Debug().Msg("fetching token from GITHUB_TOKEN")
-		return githubToken, nil
+func fmtDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// getReportPath returns the file that the report should be written to according
+// to the --out and --out-dir flags, or an empty string for stdout.
+func (o *reportOptions) getReportPath() string {
+	switch {
+	case len(flagOut) > 0:
+		return flagOut
+	case len(flagOutDir) > 0:
+		return filepath.Join(flagOutDir, o.getReportFileName(""))
 	}
 
-	githubOauthToken, ok := os.LookupEnv("GITHUB_OAUTH_TOKEN")
-	if ok && len(githubOauthToken) > 0 {
-		internal.Log().Debug().Msg("fetching token from GITHUB_OAUTH_TOKEN")
-		return githubOauthToken, nil
+	return ""
+}
+
+// getReportFileName names the report file in --out-dir after the window, with the
+// suffix after the period, i.e. "2026-10-05_2026-10-18-biweekly-team.md".
+func (o *reportOptions) getReportFileName(suffix string) string {
+	ext := ".md"
+	if flagOutputFormat == outputFormatJSON {
+		ext = ".json"
 	}
 
-	if _, err := exec.LookPath("gh"); err == nil {
-		internal.Log().Debug().Msg("user has gh installed")
-		var b bytes.Buffer
-		ghAuthCmd := exec.Command("gh", "auth", "token")
-		ghAuthCmd.Stdout = &b
-		if err = ghAuthCmd.Run(); err == nil {
-			token := strings.Trim(b.String(), "\t\n ")
-			if len(token) > 0 {
-				return token, nil
-			}
-			// TODO: ask user for permission to use the token
-			internal.Log().Debug().Msg("user has gh installed and is logged in")
-		}
+	return output.FileName(o.startTime, o.endTime, o.getPeriodAdj()+suffix, ext)
+}
+
+// getOutputFormat resolves the auto output format. Styled text is only used when
+// the report is shown in a terminal, since the escape codes are garbage anywhere
+// else, including in an editor.
+func getOutputFormat(reportPath string) string {
+	if flagOutputFormat != outputFormatAuto {
+		return flagOutputFormat
+	}
+
+	if flagEdit {
+		return outputFormatMarkdown
 	}
 
-	return "", errors.New("github token must be provided through GITHUB_TOKEN or GITHUB_OAUTH_TOKEN environment variables or through the gh CLI")
+	if len(reportPath) == 0 && (isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())) {
+		return outputFormatText
+	}
+
+	return outputFormatMarkdown
 }
 
-func fmtDate(t time.Time) string {
-	return t.Format("2006-01-02")
+// writeReport writes the rendered report to the path, or to stdout if the path is
+// empty.
+func writeReport(path string, rendered string) error {
+	if len(path) == 0 {
+		fmt.Println(rendered)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	if err := output.WriteFile(path, []byte(rendered+"\n"), flagForce); err != nil {
+		return err
+	}
+
+	internal.Log().Info().Str("path", path).Msg("wrote report")
+	return nil
 }
 
-func getUsername() (string, error) {
-	if _, err := exec.LookPath("gh"); err == nil {
-		internal.Log().Debug().Msg("user has gh installed")
-		var b bytes.Buffer
-		ghUserCmd := exec.Command("gh", "auth", "status")
-		ghUserCmd.Stdout = &b
-		if err = ghUserCmd.Run(); err == nil {
-			lines := strings.Split(b.String(), "\n")
-			for _, line := range lines {
-				if group := reUsername.FindStringSubmatch(line); len(group) > 1 {
-					// TODO: ask user for permission to use the token
-					internal.Log().Debug().Msg("user has gh installed and is logged in")
-					return group[2], nil
-				}
-			}
-		} else {
-			return "", err
+// getOpenEnd is where a window that runs until now ends: the start of the next
+// hour. Searches are cached by their query, which includes the window, so this
+// keeps reruns within the hour on the same queries, even with --offline. Nothing
+// has happened after now, so the report leaves nothing out.
+func getOpenEnd(now time.Time) time.Time {
+	end := now.Truncate(time.Hour)
+	if end.Before(now) {
+		end = end.Add(time.Hour)
+	}
+
+	return end
+}
+
+// getWindow resolves the --since, --until, and --period flags into the time
+// range that the report covers, and the period that the range spans. No period
+// is returned when --since is given without the window's length.
+func getWindow(cmd *cobra.Command, now time.Time) (time.Time, time.Time, util.Period, error) {
+	var startTime, endTime time.Time
+
+	if period, start, end, err := util.ParseRelativeWindow(flagPeriod, now); err == nil {
+		if cmd.Flags().Changed("since") || cmd.Flags().Changed("until") {
+			return startTime, endTime, "", fmt.Errorf("--period `%s` cannot be combined with --since or --until", flagPeriod)
 		}
+		if end.Equal(now) {
+			end = getOpenEnd(now)
+		}
+		return start, end, period, nil
+	}
+
+	period, err := util.ParsePeriod(flagPeriod)
+	if err != nil {
+		return startTime, endTime, "", fmt.Errorf("--period: %w", err)
+	}
+
+	if len(flagSince) > 0 {
+		if startTime, err = util.ParseTime(flagSince, now); err != nil {
+			return startTime, endTime, "", fmt.Errorf("--since: %w", err)
+		}
+	}
+
+	switch {
+	case len(flagUntil) > 0:
+		if endTime, err = util.ParseTime(flagUntil, now); err != nil {
+			return startTime, endTime, "", fmt.Errorf("--until: %w", err)
+		}
+		if endTime.Equal(now) {
+			endTime = getOpenEnd(now)
+		}
+	case len(flagSince) > 0 && cmd.Flags().Changed("period"):
+		endTime = period.AddTo(startTime, 1)
+	default:
+		endTime = getOpenEnd(now)
+	}
+
+	if len(flagSince) == 0 {
+		startTime = period.AddTo(endTime, -1)
+	} else if len(flagUntil) > 0 || !cmd.Flags().Changed("period") {
+		period = ""
+	}
+
+	if !startTime.Before(endTime) {
+		return startTime, endTime, "", fmt.Errorf("start of window %s must be before end of window %s",
+			util.FormatLocalTime(startTime), util.FormatLocalTime(endTime))
+	}
+
+	return startTime, endTime, period, nil
+}
+
+// reportOptions are the flags shared by every command that collects a report,
+// after applying the profile.
+type reportOptions struct {
+	profile   *config.Profile
+	ruleSet   *rules.RuleSet
+	scope     string
+	startTime time.Time
+	endTime   time.Time
+	// Period that the window spans, if it was resolved from one.
+	period util.Period
+	// Whether the window ends at now, so that it moves with every run.
+	rolling bool
+}
+
+// getPeriodAdj describes how often a report over the window is written. Windows
+// resolved from a period are described by it rather than by their length, since
+// months and quarters vary in length and windows for the current period end at
+// now.
+func (o *reportOptions) getPeriodAdj() string {
+	if len(o.period) > 0 {
+		return o.period.Adj()
+	}
+
+	return format.DurationAsAdj(o.endTime.Sub(o.startTime))
+}
+
+// resolveReportOptions applies the profile and checks the flags that every
+// command collecting a report shares, before anything is searched.
+func resolveReportOptions(cmd *cobra.Command) (*reportOptions, error) {
+	profile, err := loadProfile()
+	if err != nil {
+		return nil, err
+	}
+	if err = applyProfile(cmd.Flags(), profile); err != nil {
+		return nil, err
+	}
+
+	flagUser = strings.TrimSpace(flagUser)
+	if cmd.Flags().Changed("user") && len(flagUser) == 0 {
+		return nil, errors.New("--user must not be blank")
+	}
+
+	ruleSet, err := rules.Load(flagRules)
+	if err != nil {
+		return nil, fmt.Errorf("--rules: %w", err)
+	}
+
+	now := clk.Now()
+	startTime, endTime, period, err := getWindow(cmd, now)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := search.ScopeQualifiers(flagRepos, flagOrgs, flagExcludeRepos)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateSlackFlags(); err != nil {
+		return nil, err
 	}
 
-	return "", errors.New("")
+	return &reportOptions{
+		profile:   profile,
+		ruleSet:   ruleSet,
+		scope:     scope,
+		startTime: startTime,
+		endTime:   endTime,
+		period:    period,
+		rolling:   endTime.Equal(getOpenEnd(now)),
+	}, nil
 }
 
-func getOwnerAndRepository(htmlURL string) (string, string, error) {
-	if group := reOwnerRepository.FindStringSubmatch(htmlURL); len(group) > 2 {
-		return group[1], group[2], nil
+// newClient creates the client for --host, with the token for that host.
+func newClient(ctx context.Context) (*github.Client, error) {
+	host := normalizeHost(flagHost)
+	// Replayed responses don't need a token. Cached responses do, since they're
+	// only answered for the token that they were fetched with.
+	githubToken, err := getGitHubToken(host)
+	if err != nil && len(flagReplay) == 0 {
+		return nil, err
 	}
 
-	return "", "", errors.New("no match for owner and repository")
+	return newGitHubClient(ctx, host, githubToken)
+}
+
+// newReport collects the user's activity within the window and places it into
+// sections with the rules.
+func (o *reportOptions) newReport(cmd *cobra.Command) (*format.Report, error) {
+	ctx := cmd.Context()
+	client, err := newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := getUsername(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	internal.Log().Info().Str("username", username).Msg("got username")
+
+	c, err := o.collectReport(ctx, client, username)
+	if err != nil {
+		return nil, err
+	}
+	if err = saveRecording(); err != nil {
+		return nil, err
+	}
+	if flagExplain {
+		explain(cmd.ErrOrStderr(), "", c.placements)
+	}
+
+	return c.report, nil
+}
+
+// collected is a user's report, along with what went into it.
+type collected struct {
+	report *format.Report
+	// Rule that placed each of the user's issues and pull requests.
+	placements []*rules.Placement
+	totals     format.Totals
+}
+
+// collectReport collects the user's activity within the window and places it into
+// sections with the rules. This is safe to call for several users at once.
+func (o *reportOptions) collectReport(ctx context.Context, client *github.Client, username string) (*collected, error) {
+	internal.Log().Debug().
+		Str("username", username).
+		Str("start time", fmtDate(o.startTime)).
+		Str("end time", fmtDate(o.endTime)).
+		Msg("using time range")
+
+	c := &collector{
+		ctx:       ctx,
+		client:    client,
+		username:  username,
+		scope:     o.scope,
+		startTime: o.startTime,
+		endTime:   o.endTime,
+	}
+
+	act, err := c.collect()
+	if err != nil {
+		return nil, err
+	}
+
+	report := format.NewReport(username, o.startTime, o.endTime)
+	report.Window.Period = o.getPeriodAdj()
+	report.Scope.Repos = append(report.Scope.Repos, flagRepos...)
+	report.Scope.Orgs = append(report.Scope.Orgs, flagOrgs...)
+	report.Scope.ExcludeRepos = append(report.Scope.ExcludeRepos, flagExcludeRepos...)
+
+	sections, placements := o.ruleSet.Classify(rules.Env{
+		User:  username,
+		Start: o.startTime,
+		End:   o.endTime,
+	}, act.all())
+	for _, section := range sections {
+		report.AddSection(section.Title, section.Issues)
+	}
+	if err = addCommits(ctx, client, report); err != nil {
+		return nil, err
+	}
+	addReleases(ctx, client, report)
+	if err = addMessages(report); err != nil {
+		return nil, err
+	}
+
+	if o.profile != nil && len(o.profile.Sections) > 0 {
+		report.SelectSections(o.profile.Sections)
+	}
+
+	return &collected{
+		report:     report,
+		placements: placements,
+		totals:     act.totals(o.startTime, o.endTime),
+	}, nil
+}
+
+// applyDecisions applies what was decided in `snippet review` for reports over the
+// same window, and summarizes the result.
+func (o *reportOptions) applyDecisions(report *format.Report) (*format.Report, error) {
+	decisions, err := review.Load(o.getReviewPath(report.User))
+	if err != nil {
+		return nil, err
+	}
+
+	report = decisions.Apply(report)
+	report.Summarize(append(o.ruleSet.Summarize(report.Sections), summarizeExtraSections(report.Sections)...))
+	return report, nil
+}
+
+// renderReport renders the report with the template, or in the output format.
+func renderReport(report *format.Report, tmpl *template.Template, outputFormat string) (string, error) {
+	switch {
+	case tmpl != nil:
+		return format.RenderTemplate(report, tmpl)
+	case outputFormat == outputFormatJSON:
+		out, err := format.RenderJSON(report)
+		if err != nil {
+			return "", fmt.Errorf("render json report: %w", err)
+		}
+		return out, nil
+	case outputFormat == outputFormatMarkdown:
+		return format.RenderMarkdown(report), nil
+	}
+
+	return format.RenderText(report), nil
 }
 
 var rootCmd = &cobra.Command{
@@ -99,251 +443,123 @@ var rootCmd = &cobra.Command{
 	Short: "TODO",
 	Long:  `TODO`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		githubToken, err := getGitHubToken()
+		opts, err := resolveReportOptions(cmd)
 		if err != nil {
 			return err
 		}
 
-		username, err := getUsername()
-		if err != nil {
-			internal.Log().Err(err).Msg("no username")
-		} else {
-			internal.Log().Info().Str("username", username).Msg("got username")
+		if !lo.Contains(outputFormats, flagOutputFormat) {
+			return fmt.Errorf("unknown --output-format `%s`, expected one of %s",
+				flagOutputFormat, strings.Join(outputFormats, ", "))
 		}
 
-		ctx := cmd.Context()
-		client := github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{
-			AccessToken: githubToken,
-		})))
-
-		/* Select repos to search in? */
-
-		/*
-		 Issues that were recently created.
-		*/
-		endTime := time.Now()
-		startTime := endTime.Add(-2 * 7 * 24 * time.Hour)
-
-		internal.Log().Debug().
-			Str("start time", fmtDate(startTime)).
-			Str("end time", fmtDate(endTime)).
-			Msg("using time range")
-
-		issCreateQuery := fmt.Sprintf("author:%s created:%s..%s",
-			username,
-			fmtDate(startTime),
-			fmtDate(endTime),
-		)
-		internal.Log().Debug().
-			Str("query", issCreateQuery).
-			Msg("query issues by created time")
-		issueRes, _, err := client.Search.Issues(
-			ctx,
-			issCreateQuery,
-			&github.SearchOptions{
-				Sort:  "updated",
-				Order: "asc",
-			})
-		if err != nil {
-			return fmt.Errorf("search issues with query `%s`: %w", issCreateQuery, err)
+		// Templates render their own format.
+		var tmpl *template.Template
+		if len(flagTemplate) > 0 {
+			if flagOutputFormat != outputFormatAuto && flagOutputFormat != outputFormatMarkdown {
+				return fmt.Errorf("--template cannot be combined with --output-format `%s`", flagOutputFormat)
+			}
+			if tmpl, err = format.LoadTemplate(flagTemplate); err != nil {
+				return fmt.Errorf("--template: %w", err)
+			}
 		}
 
-		addIsMerged := func(issue *github.Issue, _ string) *format.GitHubIssue {
-			if issue.IsPullRequest() {
-				var isMerged bool
-				owner, repo, err := getOwnerAndRepository(issue.GetHTMLURL())
-				if err != nil {
-					internal.Log().Err(err).
-						Str("html_url", issue.GetHTMLURL()).
-						Msg("get owner and repository")
-				} else {
-					isMerged, _, err = client.PullRequests.IsMerged(
-						ctx,
-						owner,
-						repo,
-						issue.GetNumber())
-					if err != nil {
-						internal.Log().Err(err).Msg("check pull request is merged")
-						isMerged = false
-					}
-				}
-
-				return &format.GitHubIssue{
-					Merged: isMerged,
-					Issue:  issue,
-				}
-			}
+		if isTeam(cmd) {
+			return runTeam(cmd, opts, tmpl)
+		}
 
-			return &format.GitHubIssue{
-				Issue: issue,
+		var editorCommand string
+		if flagEdit {
+			// Escape codes would only get in the way in an editor.
+			if flagOutputFormat == outputFormatText {
+				return fmt.Errorf("--edit cannot be combined with --output-format `%s`", flagOutputFormat)
+			}
+			if editorCommand, err = editor.Command(); err != nil {
+				return fmt.Errorf("--edit: %w", err)
 			}
 		}
 
-		allIssues := lo.SliceToMap(issueRes.Issues, func(issue *github.Issue) (string, *github.Issue) {
-			return issue.GetURL(), issue
-		})
-		ghIssues := lo.MapValues(allIssues, addIsMerged)
-
-		issModQuery := fmt.Sprintf("author:%s updated:%s..%s",
-			username,
-			fmtDate(startTime),
-			fmtDate(endTime),
-		)
-		internal.Log().Debug().Str("query", issModQuery).Msg("query issues by modified time")
-		issueRes, _, err = client.Search.Issues(
-			ctx,
-			issModQuery,
-			&github.SearchOptions{
-				Sort:  "updated",
-				Order: "asc",
-			})
-		if err != nil {
-			return fmt.Errorf("search issues with query `%s`: %w", issModQuery, err)
+		// Fail before searching if the report can't be written anyways.
+		reportPath := opts.getReportPath()
+		if _, err = os.Stat(reportPath); err == nil && !flagForce {
+			return fmt.Errorf("%s already exists, use --force to overwrite it", reportPath)
 		}
 
-		for _, issue := range issueRes.Issues {
-			ghIssues[issue.GetURL()] = addIsMerged(issue, issue.GetURL())
+		report, err := opts.newReport(cmd)
+		if err != nil {
+			return err
 		}
 
-		within := func(target time.Time) bool {
-			return !startTime.After(target) && !endTime.Before(target)
+		if report, err = opts.applyDecisions(report); err != nil {
+			return err
 		}
 
-		// TODO: ask for user to input summary that can be placed in here?
-
-		var report bytes.Buffer
-		// weekly report for username: YYYY-mm-dd
-		report.WriteString(fmt.Sprintf(`# %s report for %s: %s
-
-`,
-			format.DurationAsAdj(endTime.Sub(startTime)),
-			username,
-			fmtDate(startTime)))
-
-		report.WriteString(format.FormatSection("Completed this cycle",
-			moveBy(
-				ghIssues,
-				func(ghi *format.GitHubIssue) bool {
-					return ghi.Issue.GetState() == "closed" && (within(ghi.Issue.GetCreatedAt().Time) || ghi.Issue.GetCreatedAt().Before(startTime))
-				})))
-
-		report.WriteString(format.FormatSection("Updated this cycle",
-			moveBy(
-				ghIssues,
-				func(ghi *format.GitHubIssue) bool {
-					// TODO: and has a recent comment from this user
-					return ghi.Issue.GetClosedAt().Before(startTime)
-				})))
-
-		report.WriteString(format.FormatSection("Remaining",
-			moveBy(
-				ghIssues,
-				func(ghi *format.GitHubIssue) bool {
-					return true
-				})))
-
-		// TODO: allow editing the final report
-		// TODO: write the report somewhere (dump into a file?)
-		// TODO: optional, allow json so that we can format more
-
-		/* Issues that were commented on
-		 */
-
-		/*
-		   Find PRs that were updated
-		*/
-
-		/* Find PRs that were merged
-		 */
-
-		/* Find PRs that were reviewed by username */
-
-		/* Find commits that actually made it through and attach to the PR link?
-		 */
-
-		/* Releases that had your changes?
-		 */
-
-		/* Slack
-		   Find messages in a certain time window
-		*/
-
-		//commQuery := fmt.Sprintf("author:%s author-date:>%s merge:true", username, oneWeekAgo)
-		//internal.Log().Debug().Str("query", commQuery).Msg("search commits")
-		//commRes, _, err := client.Search.Commits(ctx, commQuery, &github.SearchOptions{})
-		////internal.Log().Info().Array("issues", zerolog.Arr().Interface(result.Issues)).Msg("")
-		//if err != nil {
-		//	return fmt.Errorf("search commit: %w", err)
-		//}
-
-		fmt.Println(report.String())
-
-		/*
-			for category, issues := range categories {
-				if len(issues) == 0 {
-					continue
-				}
-
-				fmt.Println(category)
-				for _, issue := range issues {
-					fmt.Println(format.Issue(issue))
-				}
-				fmt.Println("")
-			}
+		outputFormat := getOutputFormat(reportPath)
+		out, err := renderReport(report, tmpl, outputFormat)
+		if err != nil {
+			return err
+		}
 
-			fmt.Println("remaining issues and prs:")
-			for _, issue := range ghIssues {
-				fmt.Println(format.Issue(issue))
+		if flagEdit {
+			if out, err = editReport(cmd, editorCommand, out, outputFormat); err != nil {
+				return err
 			}
-		*/
+		}
 
-		// TODO: perhaps commits should just be through the git log
-		// fmt.Println("commits:")
-		// for _, commit := range commRes.Commits {
-		// 	fmt.Println(commit.Commit.GetMessage())
-		// }
+		if err = writeReport(reportPath, out); err != nil {
+			return err
+		}
 
 		return nil
 	},
 }
 
-// Moves all items passing the filter into the output slice. Items that are
-// matching the filter are removed from the original map.
-func moveBy[T any](all map[string]T, filterFn func(T) bool) []T {
-	out := make([]T, 0, len(all))
-	for _, k := range lo.Keys(all) {
-		if filterFn(all[k]) {
-			out = append(out, all[k])
-			delete(all, k)
+// extraSummaries are the summary phrases for the sections that aren't placed by
+// the rules, keyed by their titles.
+var extraSummaries = []lo.Tuple3[string, string, string]{
+	{A: sectionOtherPullRequests, B: "pushed to %d other pull request", C: "pushed to %d other pull requests"},
+	{A: sectionDirectCommits, B: "pushed %d direct commit", C: "pushed %d direct commits"},
+	{A: sectionMessages, B: "sent %d Slack message", C: "sent %d Slack messages"},
+}
+
+// summarizeExtraSections describes the non-empty sections that the rules don't
+// place items into, i.e. "pushed 2 direct commits", since the rules only
+// summarize their own sections.
+func summarizeExtraSections(sections []*format.Section) []string {
+	return lo.FilterMap(extraSummaries, func(summary lo.Tuple3[string, string, string], _ int) (string, bool) {
+		section, ok := lo.Find(sections, func(s *format.Section) bool {
+			return strings.EqualFold(s.Title, summary.A)
+		})
+		if !ok || len(section.Items) == 0 {
+			return "", false
+		}
+		if len(section.Items) == 1 {
+			return fmt.Sprintf(summary.B, 1), true
+		}
+		return fmt.Sprintf(summary.C, len(section.Items)), true
+	})
+}
+
+// explain prints the section that each item was placed into and the rule that
+// placed it there. Team reports start each line with the user.
+func explain(w io.Writer, user string, placements []*rules.Placement) {
+	prefix := ""
+	if len(user) > 0 {
+		prefix = user + "\t"
+	}
+
+	for _, p := range placements {
+		rule := p.Rule
+		if len(rule) == 0 {
+			rule = "(default)"
 		}
+		fmt.Fprintf(w, "%s%s#%d\t%s\t%s\n", prefix, p.Issue.Repository, p.Issue.Issue.GetNumber(), p.Section, rule)
 	}
-	return out
 }
 
 func Execute() error {
+	shareFlags()
 	return rootCmd.Execute()
 }
 
-/*
-End goal: ??
-
-For the period of <start> to <end> (<duration>)...
-
-You created and merged these PRs:
-...
-
-You pulled these PRs across the finish line:
-...
-
-You started or continued work on these PRs:
-...
-
-You've filed X new issues:
-...
-
-You've updated these issues:
-...
-
-*/
-
 /* monthly snippets are maybe betteR? or biweekly? */
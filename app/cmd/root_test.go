@@ -0,0 +1,428 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/chrisyxlee/snippets/internal/editor"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/ratelimit"
+	"github.com/chrisyxlee/snippets/internal/replay"
+	"github.com/chrisyxlee/snippets/internal/review"
+	"github.com/chrisyxlee/snippets/internal/util"
+	"github.com/samber/lo"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// recordedAt is when the fixtures were recorded, so that the report windows line
+// up with the recorded searches.
+var recordedAt = time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+func TestMain(m *testing.M) {
+	// Dates without a time zone are parsed in local time.
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+// resetFlags puts every flag back to its default, since the flags are package
+// variables that would otherwise carry over between runs.
+func resetFlags(t *testing.T) {
+	for _, flags := range []*pflag.FlagSet{rootCmd.Flags(), rootCmd.PersistentFlags()} {
+		flags.VisitAll(func(f *pflag.Flag) {
+			if value, ok := f.Value.(pflag.SliceValue); ok {
+				require.NoError(t, value.Replace(nil))
+			} else {
+				require.NoError(t, f.Value.Set(f.DefValue))
+			}
+			f.Changed = false
+		})
+	}
+}
+
+// execute runs the whole command at the time that the fixtures were recorded, and
+// returns the report that it wrote. Reports written into --out-dir are left for
+// the caller to read.
+func execute(t *testing.T, args ...string) (string, error) {
+	return executeAt(t, recordedAt, args...)
+}
+
+// executeAt runs the whole command at the time, and returns the report that it
+// wrote.
+func executeAt(t *testing.T, now time.Time, args ...string) (string, error) {
+	mc := clock.NewMock()
+	mc.Set(now)
+	clk = mc
+	format.SetClock(mc)
+	ratelimit.SetClock(mc)
+
+	resetFlags(t)
+	recorder = nil
+	t.Setenv("GITHUB_TOKEN", "test")
+
+	out := filepath.Join(t.TempDir(), "report")
+	outDir := lo.Contains(args, "--out-dir")
+	if !outDir {
+		args = append([]string{"--out", out}, args...)
+	}
+	rootCmd.SetArgs(append([]string{
+		"--config", filepath.Join("testdata", "empty.yaml"),
+		"--cache-dir", filepath.Join(t.TempDir(), "cache"),
+		"--review-dir", filepath.Join(t.TempDir(), "reviews"),
+	}, args...))
+	if err := Execute(); err != nil {
+		return "", err
+	}
+	if outDir {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	return string(data), nil
+}
+
+// run runs the whole command against the recorded fixture.
+func run(t *testing.T, fixture string, args ...string) (string, error) {
+	return execute(t, append([]string{"--replay", filepath.Join("testdata", "fixtures", fixture)}, args...)...)
+}
+
+// assertGolden compares the report to the golden file, or updates the golden file
+// when running with -update.
+func assertGolden(t *testing.T, name string, got string) {
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), got)
+}
+
+func TestReport(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{
+			name:   "markdown",
+			args:   []string{"--user", "octocat", "--output-format", "markdown"},
+			golden: "octocat.md",
+		},
+		{
+			name:   "json",
+			args:   []string{"--user", "octocat", "--output-format", "json"},
+			golden: "octocat.json",
+		},
+		{
+			name:   "terse template",
+			args:   []string{"--user", "octocat", "--template", "terse"},
+			golden: "octocat-terse.md",
+		},
+		{
+			name:   "narrative template",
+			args:   []string{"--user", "octocat", "--template", "narrative"},
+			golden: "octocat-narrative.md",
+		},
+		{
+			name:   "table template",
+			args:   []string{"--user", "octocat", "--template", "table"},
+			golden: "octocat-table.md",
+		},
+		{
+			name:   "token owner",
+			args:   []string{"--output-format", "markdown"},
+			golden: "octocat.md",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := run(t, "octocat.json", tc.args...)
+			require.NoError(t, err)
+			assertGolden(t, tc.golden, got)
+		})
+	}
+}
+
+func TestReportNotRecorded(t *testing.T) {
+	_, err := run(t, "octocat.json", "--user", "hubot")
+	assert.ErrorIs(t, err, replay.ErrNotRecorded)
+}
+
+func TestTemplateWithOutputFormat(t *testing.T) {
+	_, err := run(t, "octocat.json", "--template", "terse", "--output-format", "json")
+	assert.ErrorContains(t, err, "--template cannot be combined with --output-format `json`")
+}
+
+func TestRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`policy: multi-match
+rules:
+  - name: shipped
+    section: Shipped
+    match:
+      type: pull_request
+      merged: true
+  - name: bugs
+    section: Bugs
+    match:
+      labels: [bug]
+      not:
+        state: closed
+`), 0o644))
+
+	var stderr bytes.Buffer
+	rootCmd.SetErr(&stderr)
+	t.Cleanup(func() { rootCmd.SetErr(nil) })
+
+	got, err := run(t, "octocat.json", "--user", "octocat", "--output-format", "markdown",
+		"--rules", path, "--explain")
+	require.NoError(t, err)
+	assert.Equal(t, `# biweekly report for octocat: 2026-10-04
+
+For the period of 2026-10-04 to 2026-10-18 (2 weeks), here's what octocat worked on.
+
+## Shipped
+
+- PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** <=3 days - Add sprockets to the widget (2 👍 1 🚀)
+
+## Bugs
+
+- Issue [octo/gadgets#41](https://github.com/octo/gadgets/issues/41) **active** <=2 weeks - Gadgets crash on startup _[2 comments](https://github.com/octo/gadgets/issues/41#issuecomment-1201)_ (3 👀)
+- Issue [octo/widgets#13](https://github.com/octo/widgets/issues/13) **active** <=2 weeks - Sprockets are too loud
+
+
+`, got)
+	assert.Equal(t, "octo/widgets#12\tShipped\tshipped\n"+
+		"octo/widgets#13\tBugs\tbugs\n"+
+		"octo/gadgets#41\tBugs\tbugs\n", stderr.String())
+
+	require.NoError(t, os.WriteFile(path, []byte("rules:\n  - section: Done\n    match:\n      stat: closed\n"), 0o644))
+	_, err = run(t, "octocat.json", "--user", "octocat", "--rules", path)
+	assert.ErrorContains(t, err, "field stat not found")
+}
+
+func TestEdit(t *testing.T) {
+	// The editor replaces the prompt with a summary and prunes the filed issue.
+	script := filepath.Join(t.TempDir(), "editor")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+sed -i -e 's/^<!-- Write a summary.*-->$/Sprockets shipped!/' -e '/widgets#13/d' "$1"
+`), 0o755))
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+
+	got, err := run(t, "octocat.json", "--user", "octocat", "--template", "terse", "--edit")
+	require.NoError(t, err)
+	assert.Equal(t, `Sprockets shipped!
+
+# octocat: 2026-10-04 to 2026-10-18
+
+For the period of 2026-10-04 to 2026-10-18 (2 weeks), octocat created and merged 1 pull request, started or continued work on 1 pull request, filed 1 new issue, updated 1 issue, reviewed 1 pull request, and commented on 1 other issue or pull request.
+
+**Created and merged**
+- [octo/widgets#12](https://github.com/octo/widgets/pull/12) Add sprockets to the widget
+
+**Started or continued**
+- [octo/gadgets#5](https://github.com/octo/gadgets/pull/5) Use \`+"`gears\\`"+` instead of \[cogs\]
+
+**Filed**
+
+**Updated**
+- [octo/widgets#7](https://github.com/octo/widgets/issues/7) Document the widget lifecycle
+
+**Reviewed**
+- [octo/widgets#40](https://github.com/octo/widgets/pull/40) Speed up widget rendering
+
+**Discussed**
+- [octo/gadgets#41](https://github.com/octo/gadgets/issues/41) Gadgets crash on startup
+`, got)
+}
+
+func TestEditDiscarded(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	for name, body := range map[string]string{
+		"left unchanged": "true",
+		"exit status 1":  `echo 'half' >> "$1"; exit 1`,
+	} {
+		script := filepath.Join(t.TempDir(), "editor")
+		require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"+body+"\n"), 0o755))
+		t.Setenv("EDITOR", script)
+
+		_, err := run(t, "octocat.json", "--user", "octocat", "--edit")
+		assert.ErrorContains(t, err, "report discarded", name)
+		assert.ErrorContains(t, err, name)
+	}
+
+	_, err := run(t, "octocat.json", "--user", "octocat", "--edit", "--output-format", "text")
+	assert.ErrorContains(t, err, "--edit cannot be combined with --output-format `text`")
+
+	t.Setenv("EDITOR", "")
+	_, err = run(t, "octocat.json", "--user", "octocat", "--edit")
+	assert.ErrorIs(t, err, editor.ErrNoEditor)
+}
+
+func TestReviewDecisions(t *testing.T) {
+	dir := t.TempDir()
+	decisions := &review.Decisions{Items: map[string]*review.Decision{
+		"Created and merged https://github.com/octo/widgets/pull/12": {Highlight: true, Note: "Unblocked the launch"},
+		"Filed https://github.com/octo/widgets/issues/13":            {Hidden: true},
+		"Updated https://github.com/octo/widgets/issues/7":           {Section: "Filed"},
+	}}
+	// The window rolls, so the decisions are saved for the biweek that it ends in.
+	start := util.PeriodBiweek.Truncate(recordedAt)
+	require.NoError(t, decisions.Save(review.Path(filepath.Join(dir, "reviews"), "octocat",
+		start, util.PeriodBiweek.AddTo(start, 1))))
+
+	got, err := run(t, "octocat.json", "--user", "octocat", "--template", "terse",
+		"--review-dir", filepath.Join(dir, "reviews"))
+	require.NoError(t, err)
+	assert.Contains(t, got, "**Created and merged**\n- ⭐ [octo/widgets#12](https://github.com/octo/widgets/pull/12) Add sprockets to the widget — Unblocked the launch\n")
+	assert.Contains(t, got, "**Filed**\n- [octo/widgets#7](https://github.com/octo/widgets/issues/7) Document the widget lifecycle\n")
+	assert.NotContains(t, got, "widgets#13")
+
+	got, err = run(t, "octocat.json", "--user", "octocat", "--output-format", "markdown",
+		"--review-dir", filepath.Join(dir, "reviews"))
+	require.NoError(t, err)
+	assert.Contains(t, got, "- ⭐ PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** <=3 days - Add sprockets to the widget (2 👍 1 🚀) — Unblocked the launch\n")
+	assert.Contains(t, got, "octocat created and merged 1 pull request, started or continued work on 1 pull request, filed 1 new issue, reviewed")
+}
+
+func TestReviewPath(t *testing.T) {
+	flagReviewDir = "reviews"
+	at := func(now time.Time) string {
+		o := &reportOptions{startTime: util.PeriodWeek.Truncate(now), endTime: getOpenEnd(now),
+			period: util.PeriodWeek, rolling: true}
+		return o.getReviewPath("octocat")
+	}
+
+	week := filepath.Join("reviews", "octocat", "2026-10-12_2026-10-18.json")
+	assert.Equal(t, week, at(time.Date(2026, time.October, 13, 9, 30, 0, 0, time.Local)))
+	assert.Equal(t, week, at(time.Date(2026, time.October, 16, 17, 0, 0, 0, time.Local)))
+
+	o := &reportOptions{
+		startTime: time.Date(2026, time.October, 5, 0, 0, 0, 0, time.Local),
+		endTime:   time.Date(2026, time.October, 12, 0, 0, 0, 0, time.Local),
+		period:    util.PeriodWeek,
+	}
+	assert.Equal(t, filepath.Join("reviews", "octocat", "2026-10-05_2026-10-11.json"), o.getReviewPath("octocat"))
+}
+
+func TestConfig(t *testing.T) {
+	dir := t.TempDir()
+	_, err := run(t, "octocat.json", "--config", filepath.Join(dir, "missing.yaml"))
+	assert.ErrorContains(t, err, "load configuration")
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("default_profile: team\nprofiles:\n  team:\n    user: octocat\n    team: [octocat, hubot]\n"), 0o644))
+	_, err = run(t, "octocat.json", "--config", path)
+	assert.EqualError(t, err, "profile `team`: user and team cannot be combined, set only one of them")
+}
+
+func TestNormalizeHost(t *testing.T) {
+	for host, want := range map[string]string{
+		"github.com":                  "github.com",
+		"https://api.github.com/":     "github.com",
+		" API.GitHub.com ":            "github.com",
+		"https://github.example.com/": "github.example.com",
+		"api.github.example.com":      "api.github.example.com",
+	} {
+		assert.Equal(t, want, normalizeHost(host), host)
+		assert.Equal(t, want != defaultHost, isEnterpriseHost(normalizeHost(host)), host)
+	}
+}
+
+func TestReviewSharesFlags(t *testing.T) {
+	shareFlags()
+
+	for _, name := range []string{"user", "since", "rules", "git-repo", "releases", "slack-export", "review-dir"} {
+		assert.NotNil(t, reviewCmd.Flags().Lookup(name), name)
+	}
+	for _, name := range append(append([]string{}, renderFlags...), teamFlags...) {
+		assert.Nil(t, reviewCmd.Flags().Lookup(name), name)
+	}
+}
+
+func TestGitRepo(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	git := func(date string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	commit := func(date string, file string, message string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte("line\nline\n"), 0o644))
+		git(date, "add", file)
+		git(date, "commit", "--quiet", "-m", message)
+	}
+
+	git("", "init", "--quiet")
+	git("", "config", "user.email", "octocat@github.com")
+	git("", "config", "user.name", "Octocat")
+	git("", "remote", "add", "origin", "https://github.com/octo/widgets.git")
+	commit("2026-10-01T12:00:00Z", "old.txt", "Before the window")
+	commit("2026-10-14T12:00:00Z", "sprockets.txt", "Add sprockets (#12)")
+	// The pull request was merged before the window, so it isn't in the report.
+	commit("2026-10-15T12:00:00Z", "other.txt", "Follow up on the launch (#99)")
+	commit("2026-10-16T12:00:00Z", "docs.txt", "Fix the docs")
+
+	got, err := run(t, "octocat.json", "--user", "octocat", "--output-format", "markdown", "--git-repo", dir)
+	require.NoError(t, err)
+	assert.Contains(t, got, "- PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** <=3 days - Add sprockets to the widget (2 👍 1 🚀)\n"+
+		"  - [")
+	assert.Contains(t, got, ") Add sprockets (\\#12) (+2 -0)\n")
+	assert.Contains(t, got, "## Other pull requests\n\n"+
+		"- PR [octo/widgets#99](https://github.com/octo/widgets/pull/99) - Follow up on the launch\n"+
+		"  - [")
+	assert.Contains(t, got, ") Follow up on the launch (\\#99) (+2 -0)\n")
+	assert.Contains(t, got, "## Direct commits\n\n- Commit [octo/widgets@")
+	assert.Contains(t, got, ") - Fix the docs (+2 -0)\n")
+	assert.Contains(t, got, ", pushed to 1 other pull request, and pushed 1 direct commit.")
+	assert.NotContains(t, got, "Before the window")
+
+	got, err = run(t, "octocat.json", "--user", "octocat", "--output-format", "markdown",
+		"--git-repo", dir, "--git-email", "hubot@github.com")
+	require.NoError(t, err)
+	assert.NotContains(t, got, "Direct commits")
+	assert.NotContains(t, got, "Other pull requests")
+
+	_, err = run(t, "octocat.json", "--user", "octocat", "--git-repo", t.TempDir())
+	assert.ErrorContains(t, err, "origin is not a GitHub repository")
+}
+
+func TestSlackExport(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "general"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "channels.json"),
+		[]byte(`[{"id": "C01GENERAL", "name": "general"}]`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "general", "2026-10-10.json"), []byte(`[
+  {"type": "message", "user": "U01OCTOCAT", "text": "Sprockets are out!", "ts": "1791644400.000100",
+   "reply_count": 2, "reactions": [{"name": "tada", "count": 3}]},
+  {"type": "message", "user": "U02HUBOT", "text": "Nice!", "ts": "1791645000.000100"}
+]`), 0o644))
+
+	got, err := run(t, "octocat.json", "--user", "octocat", "--output-format", "markdown",
+		"--slack-export", dir, "--slack-user", "U01OCTOCAT", "--slack-url", "https://octo.slack.com")
+	require.NoError(t, err)
+	assert.Contains(t, got, "## Discussions\n\n"+
+		"- Message [#general](https://octo.slack.com/archives/C01GENERAL/p1791644400000100) - Sprockets are out! _2 replies_ (3 🎉)\n\n")
+	assert.Contains(t, got, ", and sent 1 Slack message.")
+	assert.NotContains(t, got, "Nice!")
+
+	_, err = run(t, "octocat.json", "--user", "octocat", "--slack-export", dir, "--slack-user", "U01OCTOCAT")
+	assert.ErrorContains(t, err, "--slack-export requires --slack-url")
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/spf13/cobra"
+)
+
+var flagSchemaRollup bool
+
+func init() {
+	schemaCmd.Flags().BoolVar(&flagSchemaRollup, "rollup", false,
+		"print the schema for the team rollup written by --rollup instead")
+	rootCmd.AddCommand(schemaCmd)
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for --output-format json",
+	Long: `Print the JSON Schema describing the report written by --output-format json, or
+with --rollup, the team rollup that contains a report for each person.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if flagSchemaRollup {
+			fmt.Print(string(format.RollupSchema))
+			return
+		}
+		fmt.Print(string(format.ReportSchema))
+	},
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/slack"
+	"github.com/samber/lo"
+)
+
+// sectionMessages holds the user's messages from the Slack export.
+const sectionMessages = "Discussions"
+
+var (
+	flagSlackExport string
+	flagSlackUser   string
+	flagSlackURL    string
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&flagSlackExport, "slack-export", "",
+		"include the user's messages from the Slack workspace export at this path, as a zip file or directory")
+	rootCmd.Flags().StringVar(&flagSlackUser, "slack-user", "",
+		"Slack user ID or username of the user in --slack-export")
+	rootCmd.Flags().StringVar(&flagSlackURL, "slack-url", "",
+		"URL of the Slack workspace that messages link to, i.e. https://octo.slack.com")
+}
+
+// validateSlackFlags checks that everything needed to read the Slack export was
+// given, before anything is searched.
+func validateSlackFlags() error {
+	if len(flagSlackExport) == 0 {
+		return nil
+	}
+
+	if len(flagSlackUser) == 0 {
+		return errors.New("--slack-export requires --slack-user")
+	}
+	if u, err := url.Parse(flagSlackURL); len(flagSlackURL) == 0 || err != nil || len(u.Host) == 0 {
+		return errors.New("--slack-export requires --slack-url to be the URL of the workspace")
+	}
+
+	return nil
+}
+
+// addMessages adds a section with the user's messages from the Slack export
+// within the window.
+func addMessages(report *format.Report) (err error) {
+	if len(flagSlackExport) == 0 {
+		return nil
+	}
+
+	export, err := slack.Open(flagSlackExport)
+	if err != nil {
+		return fmt.Errorf("--slack-export: %w", err)
+	}
+	defer func() {
+		if closeErr := export.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	messages, err := export.Messages(flagSlackUser, report.Window.Start, report.Window.End)
+	if err != nil {
+		return fmt.Errorf("--slack-export: %w", err)
+	}
+	internal.Log().Debug().Int("messages", len(messages)).Msg("read slack export")
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	report.Sections = append(report.Sections, &format.Section{
+		Title: sectionMessages,
+		Items: lo.Map(messages, func(m *slack.Message, _ int) *format.Item {
+			return format.NewMessageItem(&format.Message{
+				Channel:   m.Channel,
+				Text:      m.Text,
+				SentAt:    m.SentAt,
+				Replies:   m.Replies,
+				Reactions: m.Reactions,
+				Permalink: m.Permalink(flagSlackURL),
+			})
+		}),
+	})
+
+	return nil
+}
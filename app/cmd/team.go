@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/teams"
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// How many people's activity is collected at once. They share the token's rate
+	// limit, so more wouldn't finish much sooner.
+	maxConcurrentUsers = 4
+	// How long the roster of --team is reused before resolving its members again.
+	maxRosterAge = 24 * time.Hour
+)
+
+var (
+	flagUsers       []string
+	flagTeam        string
+	flagTeamExclude []string
+	flagRefreshTeam bool
+	flagRollup      bool
+)
+
+// Flags for reporting on a team, which review doesn't share since it triages one
+// person's report at a time.
+var teamFlags = []string{"users", "team", "team-exclude", "refresh-team", "rollup"}
+
+func init() {
+	rootCmd.Flags().StringSliceVar(&flagUsers, "users", nil,
+		"GitHub logins of the team to report on, comma-separated, writing a report for each person into --out-dir")
+	rootCmd.Flags().StringVar(&flagTeam, "team", "",
+		"GitHub team to report on, formatted as org/team-slug, including the members of its child teams")
+	rootCmd.Flags().StringArrayVar(&flagTeamExclude, "team-exclude", nil,
+		"leave this member of --team out, i.e. a service account, as a login or a pattern like \"deploy-*\" (repeatable, bots are always left out)")
+	rootCmd.Flags().BoolVar(&flagRefreshTeam, "refresh-team", false,
+		fmt.Sprintf("resolve the members of --team again, instead of reusing the roster for up to %s", maxRosterAge))
+	rootCmd.Flags().BoolVar(&flagRollup, "rollup", false,
+		"combine the reports for --users or --team into one, with a section for each person and the team's totals")
+	rootCmd.MarkFlagsMutuallyExclusive("user", "users", "team")
+}
+
+// isTeam returns true if the report is for a team, rather than a single user.
+func isTeam(cmd *cobra.Command) bool {
+	return len(flagUsers) > 0 || cmd.Flags().Changed("users") || len(flagTeam) > 0
+}
+
+// getUsers returns the logins from --users without blanks or duplicates, in the
+// order that they were given.
+func getUsers() ([]string, error) {
+	users := lo.UniqBy(lo.Compact(lo.Map(flagUsers, func(user string, _ int) string {
+		return strings.TrimSpace(user)
+	})), strings.ToLower)
+	if len(users) == 0 {
+		return nil, errors.New("--users must not be blank")
+	}
+
+	return users, nil
+}
+
+// getTeamUsers returns the logins from --users, or everyone on --team who is left
+// after leaving out bots and --team-exclude.
+func getTeamUsers(ctx context.Context, client *github.Client) ([]string, error) {
+	if len(flagTeam) == 0 {
+		return getUsers()
+	}
+
+	roster, err := getRoster(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	users := roster.Logins(flagTeamExclude)
+	if len(users) == 0 {
+		return nil, fmt.Errorf("no one on team `%s` is left after leaving out bots and --team-exclude", flagTeam)
+	}
+
+	internal.Log().Info().
+		Str("team", roster.Team).
+		Strs("teams", roster.Teams).
+		Strs("users", users).
+		Time("resolved at", roster.ResolvedAt).
+		Msg("got team members")
+	return users, nil
+}
+
+// getRoster resolves the members of --team, reusing the cached roster unless it's
+// too old. Offline, the cached roster is used no matter how old it is. Recording
+// and replaying fixtures skip the cache, so that fixtures always have the team.
+func getRoster(ctx context.Context, client *github.Client) (*teams.Roster, error) {
+	useCache := len(flagRecord) == 0 && len(flagReplay) == 0
+	path := teams.Path(getCacheDir(normalizeHost(flagHost)), flagTeam)
+
+	if useCache && !flagRefreshTeam {
+		roster, err := teams.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		if roster != nil && (flagOffline || clk.Since(roster.ResolvedAt) < maxRosterAge) {
+			return roster, nil
+		}
+	}
+
+	roster, err := teams.Resolve(ctx, client, flagTeam, clk.Now())
+	if err != nil {
+		return nil, fmt.Errorf("--team: %w", err)
+	}
+
+	if useCache {
+		if err = roster.Save(path); err != nil {
+			return nil, fmt.Errorf("cache team roster: %w", err)
+		}
+	}
+	return roster, nil
+}
+
+// runTeam writes a report for each of the --users or everyone on --team, or a
+// rollup of all of them with --rollup.
+func runTeam(cmd *cobra.Command, opts *reportOptions, tmpl *template.Template) error {
+	if len(flagTeam) > 0 {
+		if _, _, err := teams.Parse(flagTeam); err != nil {
+			return fmt.Errorf("--team: %w", err)
+		}
+		if err := teams.ValidateExclude(flagTeamExclude); err != nil {
+			return fmt.Errorf("--team-exclude: %w", err)
+		}
+	}
+
+	switch {
+	case len(flagTeam) > 0 && len(flagUsers) > 0:
+		return errors.New("--team cannot be combined with --users")
+	case flagEdit:
+		return fmt.Errorf("--edit cannot be combined with %s", teamFlag())
+	case len(flagGitRepos) > 0:
+		// Local commits and Slack messages can only be matched to one person.
+		return fmt.Errorf("--git-repo cannot be combined with %s", teamFlag())
+	case len(flagSlackExport) > 0:
+		return fmt.Errorf("--slack-export cannot be combined with %s", teamFlag())
+	case flagRollup && tmpl != nil:
+		return errors.New("--template cannot be combined with --rollup")
+	case !flagRollup && len(flagOutDir) == 0:
+		return fmt.Errorf("%s writes a report for each person into --out-dir, or combine them with --rollup", teamFlag())
+	}
+
+	ctx := cmd.Context()
+	client, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	users, err := getTeamUsers(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	if flagRollup {
+		rollupPath := flagOut
+		if len(flagOutDir) > 0 {
+			rollupPath = filepath.Join(flagOutDir, opts.getReportFileName("-team"))
+		}
+		paths = []string{rollupPath}
+	} else {
+		paths = lo.Map(users, func(user string, _ int) string {
+			return filepath.Join(flagOutDir, user, opts.getReportFileName(""))
+		})
+	}
+
+	// Fail before searching if the reports can't be written anyways.
+	for _, path := range paths {
+		if _, err = os.Stat(path); err == nil && !flagForce {
+			return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+		}
+	}
+
+	team, err := opts.collectTeam(ctx, client, users)
+	if err != nil {
+		return err
+	}
+	if err = saveRecording(); err != nil {
+		return err
+	}
+
+	people := make([]*format.Person, len(team))
+	for i, c := range team {
+		if flagExplain {
+			explain(cmd.ErrOrStderr(), users[i], c.placements)
+		}
+
+		report, err := opts.applyDecisions(c.report)
+		if err != nil {
+			return err
+		}
+		people[i] = &format.Person{User: users[i], Totals: c.totals, Report: report}
+	}
+
+	if flagRollup {
+		rollup := format.NewRollup(people)
+		rollup.Team = flagTeam
+		out, err := renderRollup(rollup, getOutputFormat(paths[0]))
+		if err != nil {
+			return err
+		}
+		return writeReport(paths[0], out)
+	}
+
+	for i, person := range people {
+		out, err := renderReport(person.Report, tmpl, getOutputFormat(paths[i]))
+		if err != nil {
+			return err
+		}
+		if err = writeReport(paths[i], out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// teamFlag is the flag that the team was given with, for errors.
+func teamFlag() string {
+	if len(flagTeam) > 0 {
+		return "--team"
+	}
+
+	return "--users"
+}
+
+// collectTeam collects the report for each of the users concurrently, in the
+// order of the users.
+func (o *reportOptions) collectTeam(ctx context.Context, client *github.Client, users []string) ([]*collected, error) {
+	team := make([]*collected, len(users))
+	errs := make([]error, len(users))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentUsers)
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, user string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			internal.Log().Info().Str("username", user).Msg("collecting report")
+			team[i], errs[i] = o.collectReport(ctx, client, user)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("report for %s: %w", user, errs[i])
+			}
+		}(i, user)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return team, nil
+}
+
+// renderRollup renders the rollup in the output format.
+func renderRollup(rollup *format.Rollup, outputFormat string) (string, error) {
+	switch outputFormat {
+	case outputFormatJSON:
+		out, err := format.RenderRollupJSON(rollup)
+		if err != nil {
+			return "", fmt.Errorf("render json rollup: %w", err)
+		}
+		return out, nil
+	case outputFormatMarkdown:
+		return format.RenderRollupMarkdown(rollup), nil
+	}
+
+	return format.RenderRollupText(rollup), nil
+}
@@ -0,0 +1,61 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/fakegithub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFunctional runs the snippets binary found in PATH against a fake GitHub API,
+// i.e. through `make test/functional`.
+func TestFunctional(t *testing.T) {
+	if testing.Short() {
+		t.Skip("functional test")
+	}
+
+	bin, err := exec.LookPath("snippets")
+	if err != nil {
+		// CI installs the binary, so it missing there is a broken build rather than
+		// a reason to skip.
+		if len(os.Getenv("CI")) > 0 {
+			t.Fatal("snippets binary isn't in PATH")
+		}
+		t.Skip("snippets binary isn't in PATH, run `make test/functional`")
+	}
+
+	s := fakegithub.New("octocat")
+	defer s.Close()
+
+	createdAt := time.Now().Add(-48 * time.Hour)
+	s.AddIssues(&fakegithub.Issue{
+		Repo:      "octo/widgets",
+		Number:    13,
+		Title:     "Sprockets are too loud",
+		Author:    "octocat",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	})
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "report.md")
+	cmd := exec.Command(bin,
+		"--api-url", s.URL,
+		"--cache-dir", filepath.Join(dir, "cache"),
+		"--output-format", "markdown",
+		"--out", out)
+	// Without a configuration file at the default path, there's no profile.
+	cmd.Env = append(os.Environ(), "GITHUB_TOKEN=test", "XDG_CONFIG_HOME="+dir)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	report, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "[octo/widgets#13](https://github.com/octo/widgets/issues/13) **active**")
+	assert.Contains(t, string(report), "Sprockets are too loud")
+}
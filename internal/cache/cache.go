@@ -0,0 +1,241 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+
+	"github.com/chrisyxlee/snippets/internal"
+)
+
+// ErrNotCached is returned in offline mode for requests that have no cached
+// response.
+var ErrNotCached = errors.New("response is not cached, run without --offline to fetch it")
+
+// DefaultDir is where responses are cached unless otherwise specified:
+// $XDG_CACHE_HOME/snippets, or ~/.cache/snippets if XDG_CACHE_HOME isn't set.
+func DefaultDir() string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if len(dir) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+
+	return filepath.Join(dir, "snippets")
+}
+
+// Transport caches successful responses on disk.
+//
+// GET requests with a cached ETag are revalidated with If-None-Match, and GitHub
+// doesn't count the 304 Not Modified responses against the rate limit. Other
+// requests, like GraphQL queries, are always sent, and are only cached so that
+// they can be answered offline.
+//
+// What a request returns depends on who made it, i.e. for private repositories, so
+// responses are only shared between requests with the same Authorization header.
+// The header has to be set before the request reaches the Transport.
+type Transport struct {
+	// Directory that the responses are cached in.
+	Dir string
+	// Answer every request from the cache without touching the network.
+	Offline bool
+	// Transport that sends the requests, http.DefaultTransport if nil.
+	Base http.RoundTripper
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+
+	return t.Base
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, req, err := cacheKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := t.load(key, req)
+	if err != nil {
+		internal.Log().Debug().Err(err).Str("url", req.URL.String()).Msg("read cached response")
+	}
+
+	if t.Offline {
+		if cached == nil {
+			return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, ErrNotCached)
+		}
+		return cached, nil
+	}
+
+	revalidate := cached != nil && req.Method == http.MethodGet && len(cached.Header.Get("ETag")) > 0
+	if revalidate {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.Header.Get("ETag"))
+	}
+
+	res, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if revalidate && res.StatusCode == http.StatusNotModified {
+		internal.Log().Debug().Str("url", req.URL.String()).Msg("cached response is still valid")
+		res.Body.Close()
+		// The rate limit headers are current, unlike the cached ones.
+		for _, header := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Used"} {
+			if value := res.Header.Get(header); len(value) > 0 {
+				cached.Header.Set(header, value)
+			}
+		}
+		return cached, nil
+	}
+	if cached != nil {
+		cached.Body.Close()
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return res, nil
+	}
+
+	return t.store(key, res)
+}
+
+// cacheKey identifies the request by everything that changes the response,
+// including the token it was made with. Only the hash of the token ends up on
+// disk. The request is returned with its body restored, since the body has to be
+// read.
+func cacheKey(req *http.Request) (string, *http.Request, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n%s\n%s\n", req.Method, req.URL, req.Header.Get("Accept"), req.Header.Get("Authorization"))
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", nil, fmt.Errorf("read request body: %w", err)
+		}
+
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		h.Write(body)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), req, nil
+}
+
+func (t *Transport) path(key string) string {
+	return filepath.Join(t.Dir, key[:2], key)
+}
+
+// load returns the cached response for the key, or nil if there isn't one.
+func (t *Transport) load(key string, req *http.Request) (*http.Response, error) {
+	data, err := os.ReadFile(t.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+}
+
+// store writes the response to the cache and returns an equivalent response,
+// since the body of the original response is consumed.
+func (t *Transport) store(key string, res *http.Response) (*http.Response, error) {
+	data, err := httputil.DumpResponse(res, true)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	res.Body.Close()
+
+	if err = writeFile(t.path(key), data); err != nil {
+		internal.Log().Warn().Err(err).Msg("cache response")
+	}
+
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), res.Request)
+}
+
+// writeFile atomically writes the data to the path, so that concurrent runs never
+// read a partially written response. Responses can be private, so the file is
+// only readable by the user.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Stats describes what's in the cache.
+type Stats struct {
+	Entries int
+	Bytes   int64
+}
+
+// ReadStats counts the responses in the cache directory. A missing directory is
+// an empty cache.
+func ReadStats(dir string) (Stats, error) {
+	var stats Stats
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == dir {
+			return fs.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return Stats{}, fmt.Errorf("read cache %s: %w", dir, err)
+	}
+
+	return stats, nil
+}
+
+// Clear removes every response from the cache directory.
+func Clear(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("clear cache %s: %w", dir, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package cache_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chrisyxlee/snippets/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type counts struct {
+	requests     int
+	notModified  int
+	revalidating int
+}
+
+func newServer(t *testing.T, c *counts) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.requests++
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(5000-c.requests))
+
+		if r.Method == http.MethodPost {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			fmt.Fprintf(w, "posted %s", body)
+			return
+		}
+
+		if len(r.Header.Get("If-None-Match")) > 0 {
+			c.revalidating++
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			c.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintf(w, "hello %s", r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	res, err := client.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res, string(body)
+}
+
+func TestTransportRevalidates(t *testing.T) {
+	t.Parallel()
+
+	var c counts
+	server := newServer(t, &c)
+	dir := t.TempDir()
+	client := &http.Client{Transport: &cache.Transport{Dir: dir}}
+
+	_, body := get(t, client, server.URL+"/search")
+	assert.Equal(t, "hello /search", body)
+	assert.Equal(t, 0, c.revalidating)
+
+	res, body := get(t, client, server.URL+"/search")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "hello /search", body)
+	assert.Equal(t, 1, c.notModified)
+	// The rate limit comes from the latest response rather than the cache.
+	assert.Equal(t, "4998", res.Header.Get("X-RateLimit-Remaining"))
+
+	stats, err := cache.ReadStats(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Greater(t, stats.Bytes, int64(0))
+}
+
+func TestTransportOffline(t *testing.T) {
+	t.Parallel()
+
+	var c counts
+	server := newServer(t, &c)
+	dir := t.TempDir()
+	online := &http.Client{Transport: &cache.Transport{Dir: dir}}
+	offline := &http.Client{Transport: &cache.Transport{Dir: dir, Offline: true}}
+
+	_, body := get(t, online, server.URL+"/a")
+	assert.Equal(t, "hello /a", body)
+
+	res, err := online.Post(server.URL+"/graphql", "application/json", strings.NewReader("query"))
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, 2, c.requests)
+
+	_, body = get(t, offline, server.URL+"/a")
+	assert.Equal(t, "hello /a", body)
+
+	res, err = offline.Post(server.URL+"/graphql", "application/json", strings.NewReader("query"))
+	require.NoError(t, err)
+	posted, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, "posted query", string(posted))
+
+	_, err = offline.Post(server.URL+"/graphql", "application/json", strings.NewReader("other query"))
+	assert.True(t, errors.Is(err, cache.ErrNotCached))
+
+	_, err = offline.Get(server.URL + "/b")
+	assert.True(t, errors.Is(err, cache.ErrNotCached))
+	assert.Equal(t, 2, c.requests)
+}
+
+// withToken sets the Authorization header, like the oauth2 transport in front of
+// the cache.
+type withToken struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (w *withToken) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+w.token)
+	return w.base.RoundTrip(req)
+}
+
+func TestTransportPerToken(t *testing.T) {
+	t.Parallel()
+
+	var c counts
+	server := newServer(t, &c)
+	dir := t.TempDir()
+	octocat := &http.Client{Transport: &withToken{token: "octocat", base: &cache.Transport{Dir: dir}}}
+	hubot := &http.Client{Transport: &withToken{token: "hubot", base: &cache.Transport{Dir: dir, Offline: true}}}
+
+	_, body := get(t, octocat, server.URL+"/private")
+	assert.Equal(t, "hello /private", body)
+
+	_, err := hubot.Get(server.URL + "/private")
+	assert.True(t, errors.Is(err, cache.ErrNotCached))
+
+	offline := &http.Client{Transport: &withToken{token: "octocat", base: &cache.Transport{Dir: dir, Offline: true}}}
+	_, body = get(t, offline, server.URL+"/private")
+	assert.Equal(t, "hello /private", body)
+	assert.Equal(t, 1, c.requests)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "octocat")
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+
+	var c counts
+	server := newServer(t, &c)
+	dir := t.TempDir()
+	client := &http.Client{Transport: &cache.Transport{Dir: dir}}
+	get(t, client, server.URL+"/a")
+
+	require.NoError(t, cache.Clear(dir))
+	stats, err := cache.ReadStats(dir)
+	require.NoError(t, err)
+	assert.Equal(t, cache.Stats{}, stats)
+
+	get(t, client, server.URL+"/a")
+	assert.Equal(t, 0, c.revalidating)
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the contents of the configuration file.
+type Config struct {
+	// Profile to use when none is selected.
+	DefaultProfile string              `yaml:"default_profile"`
+	Profiles       map[string]*Profile `yaml:"profiles"`
+}
+
+// Profile is a named set of defaults for generating a report. Flags given on the
+// command line take precedence over the profile.
+type Profile struct {
+	// GitHub host, i.e. a GitHub Enterprise Server host like github.example.com.
+	Host         string   `yaml:"host"`
+	User         string   `yaml:"user"`
+	Repos        []string `yaml:"repos"`
+	Orgs         []string `yaml:"orgs"`
+	ExcludeRepos []string `yaml:"exclude_repos"`
+	Period       string   `yaml:"period"`
+	OutputFormat string   `yaml:"output_format"`
+	// Titles of the sections to include in the report, in order.
+	Sections []string `yaml:"sections"`
+	// Built-in template or path to the text/template file used to render the
+	// report.
+	Template string `yaml:"template"`
+	// Path to the YAML file with the rules that place items into sections.
+	Rules string `yaml:"rules"`
+	// Paths to local git repositories to include the user's commits from.
+	GitRepos []string `yaml:"git_repos"`
+	// Author emails of the user's commits, instead of each repository's
+	// user.email.
+	GitEmails []string `yaml:"git_emails"`
+	// Whether to show the first release that shipped each merged pull request.
+	Releases bool `yaml:"releases"`
+	// Whether to add a section with the releases published within the window.
+	ReleasedSection bool `yaml:"released_section"`
+	// Path to a Slack workspace export, as a zip file or the directory it was
+	// extracted to.
+	SlackExport string `yaml:"slack_export"`
+	// Slack user ID or username of the user.
+	SlackUser string `yaml:"slack_user"`
+	// URL of the Slack workspace, i.e. https://octo.slack.com.
+	SlackURL string `yaml:"slack_url"`
+	// GitHub logins of the team to report on, instead of a single user.
+	Team []string `yaml:"team"`
+	// GitHub team to report on instead, formatted as org/team-slug.
+	GitHubTeam string `yaml:"github_team"`
+	// Logins or patterns of the members of the GitHub team to leave out, i.e.
+	// service accounts.
+	TeamExclude []string `yaml:"team_exclude"`
+	// Whether to combine the team's reports into a single rollup, instead of
+	// writing a report for each person.
+	Rollup bool `yaml:"rollup"`
+}
+
+// DefaultPath is where the configuration file lives unless otherwise specified:
+// $XDG_CONFIG_HOME/snippets/config.yaml, or ~/.config/snippets/config.yaml if
+// XDG_CONFIG_HOME isn't set.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if len(dir) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "snippets", "config.yaml")
+}
+
+// Load reads the configuration file at the path. Keys that aren't recognized are
+// ignored, use Validate to find them.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Parse parses the contents of a configuration file.
+func Parse(data []byte) (*Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+
+	return &c, nil
+}
+
+// Profile returns the profile with the name. If the name is empty, the default
+// profile is returned, or nil if there isn't one.
+func (c *Config) Profile(name string) (*Profile, error) {
+	if len(name) == 0 {
+		name = c.DefaultProfile
+		if len(name) == 0 {
+			return nil, nil
+		}
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok || p == nil {
+		names := lo.Keys(c.Profiles)
+		sort.Strings(names)
+		return nil, fmt.Errorf("no profile named `%s`, expected one of [%s]", name, strings.Join(names, ", "))
+	}
+	if err := p.Check(); err != nil {
+		return nil, fmt.Errorf("profile `%s`: %w", name, err)
+	}
+
+	return p, nil
+}
+
+// Check returns an error if the profile sets keys that can't be combined, like the
+// flags that they stand in for. A report is on a user, a list of logins, or a
+// GitHub team, so at most one of user, team, and github_team can be set.
+func (p *Profile) Check() error {
+	var keys []string
+	if len(p.User) > 0 {
+		keys = append(keys, "user")
+	}
+	if len(p.Team) > 0 {
+		keys = append(keys, "team")
+	}
+	if len(p.GitHubTeam) > 0 {
+		keys = append(keys, "github_team")
+	}
+
+	if len(keys) > 1 {
+		return fmt.Errorf("%s cannot be combined, set only one of them", strings.Join(keys, " and "))
+	}
+	return nil
+}
+
+// Validate parses the contents of a configuration file and reports every key that
+// isn't recognized along with its line number.
+func Validate(data []byte) []error {
+	var root yaml.Node
+	err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&root)
+	if errors.Is(err, io.EOF) {
+		// An empty file is valid.
+		return nil
+	}
+	if err != nil {
+		return []error{fmt.Errorf("parse config: %w", err)}
+	}
+
+	var errs []error
+	for _, doc := range root.Content {
+		errs = append(errs, unknownKeys(doc, reflect.TypeOf(Config{}), "")...)
+	}
+
+	if _, err := Parse(data); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// unknownKeys walks the node alongside the type that it will be decoded into, and
+// reports any mapping keys that don't match a field.
+func unknownKeys(node *yaml.Node, t reflect.Type, path string) []error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var errs []error
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fieldByTag(t, key.Value)
+			if !ok {
+				errs = append(errs, fmt.Errorf("line %d: unknown key `%s`", key.Line, joinPath(path, key.Value)))
+				continue
+			}
+			errs = append(errs, unknownKeys(value, field.Type, joinPath(path, key.Value))...)
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			errs = append(errs, unknownKeys(value, t.Elem(), joinPath(path, key.Value))...)
+		}
+	case node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice:
+		for i, item := range node.Content {
+			errs = append(errs, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return errs
+}
+
+func fieldByTag(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == key {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+func joinPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,103 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chrisyxlee/snippets/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `default_profile: work
+profiles:
+  work:
+    user: octocat
+    repos:
+      - octo/widgets
+    orgs: [acme]
+    period: last-week
+    output_format: markdown
+    sections:
+      - Completed this cycle
+  side:
+    repos: [octocat/dotfiles]
+    period: month
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	c, err := config.Parse([]byte(testConfig))
+	require.NoError(t, err)
+
+	p, err := c.Profile("")
+	require.NoError(t, err)
+	assert.Equal(t, &config.Profile{
+		User:         "octocat",
+		Repos:        []string{"octo/widgets"},
+		Orgs:         []string{"acme"},
+		Period:       "last-week",
+		OutputFormat: "markdown",
+		Sections:     []string{"Completed this cycle"},
+	}, p)
+
+	p, err = c.Profile("side")
+	require.NoError(t, err)
+	assert.Equal(t, "month", p.Period)
+
+	_, err = c.Profile("missing")
+	assert.ErrorContains(t, err, "[side, work]")
+
+	c.DefaultProfile = ""
+	p, err = c.Profile("")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	c.Profiles["side"].GitHubTeam = "octo/eng"
+	c.Profiles["side"].Team = []string{"octocat", "hubot"}
+	_, err = c.Profile("side")
+	assert.EqualError(t, err, "profile `side`: team and github_team cannot be combined, set only one of them")
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	_, err := config.Load(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0o600))
+	c, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Len(t, c.Profiles, 2)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, config.Validate([]byte(testConfig)))
+
+	errs := config.Validate([]byte(`default_profile: work
+profile: {}
+profiles:
+  work:
+    repos: [octo/widgets]
+    exclude_repo: [octo/legacy]
+    period: week
+`))
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "line 2: unknown key `profile`")
+	assert.EqualError(t, errs[1], "line 6: unknown key `profiles.work.exclude_repo`")
+
+	errs = config.Validate([]byte("profiles: [work]\n"))
+	assert.Len(t, errs, 1)
+
+	assert.Empty(t, config.Validate([]byte("")))
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	assert.Equal(t, "/xdg/snippets/config.yaml", config.DefaultPath())
+}
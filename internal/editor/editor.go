@@ -0,0 +1,86 @@
+// Package editor lets the user edit text in their own editor.
+package editor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoEditor is returned when neither $VISUAL nor $EDITOR is set.
+var ErrNoEditor = errors.New("set $VISUAL or $EDITOR to edit the report")
+
+// ErrUnchanged is returned when the user closes the editor without changing the
+// text, which is how most tools let the user back out.
+var ErrUnchanged = errors.New("left unchanged")
+
+// Command returns the user's editor, preferring $VISUAL over $EDITOR like git
+// does. The command can include arguments, i.e. "code --wait".
+func Command() (string, error) {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if value := strings.TrimSpace(os.Getenv(key)); len(value) > 0 {
+			return value, nil
+		}
+	}
+
+	return "", ErrNoEditor
+}
+
+// Editor runs an editor command on a temporary file.
+type Editor struct {
+	// Command is the editor and its arguments, which the file path is appended to.
+	// It's run by the shell like git runs it, so it can quote paths with spaces.
+	Command string
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+// Edit writes the text into a temporary file with the extension, opens it in the
+// editor, and returns what the user saved. The temporary file is always removed.
+// If the editor fails or the text is left unchanged, an error is returned and
+// the edits are discarded.
+func (e *Editor) Edit(ctx context.Context, text string, ext string) (string, error) {
+	if len(strings.TrimSpace(e.Command)) == 0 {
+		return "", ErrNoEditor
+	}
+
+	f, err := os.CreateTemp("", "snippets-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("create file to edit: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.WriteString(text); err != nil {
+		f.Close()
+		return "", fmt.Errorf("write %s: %w", f.Name(), err)
+	}
+	if err = f.Close(); err != nil {
+		return "", fmt.Errorf("close %s: %w", f.Name(), err)
+	}
+
+	// The file is the shell's only positional parameter, so the command doesn't need
+	// to quote it.
+	cmd := exec.CommandContext(ctx, "sh", "-c", e.Command+` "$@"`, e.Command, f.Name())
+	cmd.Stdin = e.Stdin
+	cmd.Stdout = e.Stdout
+	cmd.Stderr = e.Stderr
+	if err = cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor `%s`: %w", e.Command, err)
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", f.Name(), err)
+	}
+	if bytes.Equal(edited, []byte(text)) {
+		return "", ErrUnchanged
+	}
+
+	return string(edited), nil
+}
@@ -0,0 +1,96 @@
+package editor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chrisyxlee/snippets/internal/editor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// script writes a shell script that edits the file given as its last argument.
+func script(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "editor")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755))
+	return path
+}
+
+func TestCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	_, err := editor.Command()
+	assert.ErrorIs(t, err, editor.ErrNoEditor)
+
+	t.Setenv("EDITOR", "vi")
+	cmd, err := editor.Command()
+	require.NoError(t, err)
+	assert.Equal(t, "vi", cmd)
+
+	t.Setenv("VISUAL", "code --wait")
+	cmd, err = editor.Command()
+	require.NoError(t, err)
+	assert.Equal(t, "code --wait", cmd)
+}
+
+func TestEdit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name    string
+		script  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "edited",
+			script: `printf 'Shipped sprockets.\n' | cat - "$1" > "$1.new" && mv "$1.new" "$1"`,
+			want:   "Shipped sprockets.\n# report\n",
+		},
+		{
+			name:    "unchanged",
+			script:  `true`,
+			wantErr: "left unchanged",
+		},
+		{
+			name:    "failed",
+			script:  `echo 'partial' >> "$1"; exit 3`,
+			wantErr: "exit status 3",
+		},
+	} {
+		e := &editor.Editor{Command: script(t, tc.script)}
+		got, err := e.Edit(ctx, "# report\n", ".md")
+		if len(tc.wantErr) > 0 {
+			assert.ErrorContains(t, err, tc.wantErr, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.want, got, tc.name)
+	}
+}
+
+func TestEditArguments(t *testing.T) {
+	t.Parallel()
+
+	// The file is appended after the editor's own arguments.
+	e := &editor.Editor{Command: script(t, `printf '%s\n' "$1" > "$2"`) + " --wait"}
+	got, err := e.Edit(context.Background(), "", ".md")
+	require.NoError(t, err)
+	assert.Equal(t, "--wait\n", got)
+
+	// Paths with spaces can be quoted like in a shell.
+	dir := filepath.Join(t.TempDir(), "Sublime Text")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	path := filepath.Join(dir, "subl")
+	require.NoError(t, os.Rename(script(t, `printf '%s\n' "$1" > "$2"`), path))
+	e = &editor.Editor{Command: "'" + path + "' --wait"}
+	got, err = e.Edit(context.Background(), "", ".md")
+	require.NoError(t, err)
+	assert.Equal(t, "--wait\n", got)
+
+	_, err = (&editor.Editor{}).Edit(context.Background(), "", ".md")
+	assert.ErrorIs(t, err, editor.ErrNoEditor)
+}
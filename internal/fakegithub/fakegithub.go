@@ -0,0 +1,689 @@
+// Package fakegithub is an in-process fake of the parts of the GitHub API that
+// snippets uses, for testing without network access.
+package fakegithub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+)
+
+const (
+	rateLimit = 5000
+	// Largest page that the API returns, no matter what is asked for.
+	maxPerPage = 100
+)
+
+// Issue is an issue or pull request to seed the server with.
+type Issue struct {
+	// Full name of the repository, i.e. "owner/name".
+	Repo      string
+	Number    int
+	Title     string
+	Author    string
+	State     string
+	Labels    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  *time.Time
+	// Counts of each reaction, keyed by the GitHub reaction name, i.e. "+1".
+	Reactions map[string]int
+	Comments  []Comment
+
+	PullRequest    bool
+	MergedAt       *time.Time
+	MergeCommitSHA string
+	ReviewDecision string
+	Additions      int
+	Deletions      int
+	Reviews        []Review
+	ReviewComments []Comment
+}
+
+type Comment struct {
+	ID        int64
+	Author    string
+	CreatedAt time.Time
+}
+
+type Review struct {
+	ID     int64
+	Author string
+	// One of APPROVED, CHANGES_REQUESTED, or COMMENTED.
+	State       string
+	SubmittedAt time.Time
+}
+
+// Release is a published release to seed the server with.
+type Release struct {
+	// Full name of the repository, i.e. "owner/name".
+	Repo        string
+	Tag         string
+	Name        string
+	PublishedAt time.Time
+	Draft       bool
+	// TagOnly releases are tags that were pushed without publishing a release. The
+	// tagged commit was committed at PublishedAt.
+	TagOnly bool
+	// SHAs of the commits that the release's tag contains, which is what comparing
+	// the commits to the tag is answered from. The last one is the tagged commit.
+	Commits []string
+}
+
+// Team is a team in an organization to seed the server with.
+type Team struct {
+	Org  string
+	Slug string
+	// Slug of the team that this team is nested under, if any.
+	Parent string
+	// Logins of the members of this team, not counting its child teams. Logins
+	// ending in "[bot]" are bots.
+	Members []string
+}
+
+// Server is a fake GitHub API. Point a client at URL, which serves both the REST
+// API and the GraphQL API at URL/graphql.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// Login of the owner of the token.
+	user     string
+	issues   []*Issue
+	releases []*Release
+	teams    []*Team
+	failures []func(w http.ResponseWriter)
+	requests int
+}
+
+// New starts a fake GitHub API where the token belongs to the user. Close it when
+// done.
+func New(user string) *Server {
+	s := &Server{user: user}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", s.handleUser)
+	mux.HandleFunc("/rate_limit", s.handleRateLimit)
+	mux.HandleFunc("/search/issues", s.handleSearchIssues)
+	mux.HandleFunc("/repos/", s.handleRepos)
+	mux.HandleFunc("/orgs/", s.handleOrgs)
+	mux.HandleFunc("/graphql", s.handleGraphQL)
+	s.Server = httptest.NewServer(s.intercept(mux))
+
+	return s
+}
+
+// AddIssues seeds the server with issues and pull requests.
+func (s *Server) AddIssues(issues ...*Issue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.issues = append(s.issues, issues...)
+}
+
+// AddReleases seeds the server with releases.
+func (s *Server) AddReleases(releases ...*Release) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.releases = append(s.releases, releases...)
+}
+
+// AddTeams seeds the server with teams.
+func (s *Server) AddTeams(teams ...*Team) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.teams = append(s.teams, teams...)
+}
+
+// Requests is the number of requests that the server has received, including
+// the ones that failed.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+// InjectRateLimit makes the next n requests fail because the primary rate limit
+// was exceeded. The rate limit resets right away, since the reset is in the past
+// no matter which clock the client waits on.
+func (s *Server) InjectRateLimit(n int) {
+	s.inject(n, func(w http.ResponseWriter) {
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rateLimit))
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1")
+		writeJSON(w, http.StatusForbidden, map[string]string{
+			"message":           "API rate limit exceeded for user ID 1.",
+			"documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#rate-limiting",
+		})
+	})
+}
+
+// InjectAbuse makes the next n requests fail because a secondary rate limit was
+// exceeded, asking the client to retry after the duration.
+func (s *Server) InjectAbuse(n int, retryAfter time.Duration) {
+	s.inject(n, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		writeJSON(w, http.StatusForbidden, map[string]string{
+			"message":           "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.",
+			"documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits",
+		})
+	})
+}
+
+func (s *Server) inject(n int, failure func(w http.ResponseWriter)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, failure)
+	}
+}
+
+// intercept counts the requests and fails them if a failure was injected.
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		requests := s.requests
+		var failure func(w http.ResponseWriter)
+		if len(s.failures) > 0 {
+			failure, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if failure != nil {
+			failure(w)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rateLimit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(rateLimit-requests))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, newUser(s.user))
+}
+
+func (s *Server) handleRateLimit(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	remaining := rateLimit - s.requests
+	s.mu.Unlock()
+
+	rate := map[string]any{
+		"limit":     rateLimit,
+		"remaining": remaining,
+		"reset":     time.Now().Add(time.Hour).Unix(),
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"resources": map[string]any{
+			"core":    rate,
+			"search":  rate,
+			"graphql": rate,
+		},
+		"rate": rate,
+	})
+}
+
+func (s *Server) handleSearchIssues(w http.ResponseWriter, r *http.Request) {
+	match, err := parseQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	matches := lo.Filter(s.issues, func(issue *Issue, _ int) bool {
+		return match(issue)
+	})
+	s.mu.Unlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].UpdatedAt.Before(matches[j].UpdatedAt)
+	})
+	if r.URL.Query().Get("order") == "desc" {
+		lo.Reverse(matches)
+	}
+
+	items := paginate(w, r, matches)
+	writeJSON(w, http.StatusOK, &github.IssuesSearchResult{
+		Total:             github.Int(len(matches)),
+		IncompleteResults: github.Bool(false),
+		Issues: lo.Map(items, func(issue *Issue, _ int) *github.Issue {
+			return s.newIssue(issue)
+		}),
+	})
+}
+
+// handleRepos routes /repos/{owner}/{repo}/{issues,pulls}/{number}/{resource},
+// /repos/{owner}/{repo}/releases, /repos/{owner}/{repo}/tags,
+// /repos/{owner}/{repo}/commits/{sha}, and
+// /repos/{owner}/{repo}/compare/{base}...{head}.
+func (s *Server) handleRepos(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/repos/"), "/"), "/")
+	switch {
+	case len(parts) == 3 && parts[2] == "releases":
+		s.handleReleases(w, r, parts[0]+"/"+parts[1])
+		return
+	case len(parts) == 3 && parts[2] == "tags":
+		s.handleTags(w, r, parts[0]+"/"+parts[1])
+		return
+	case len(parts) == 4 && parts[2] == "commits":
+		s.handleCommit(w, parts[0]+"/"+parts[1], parts[3])
+		return
+	case len(parts) == 4 && parts[2] == "compare":
+		s.handleCompare(w, r, parts[0]+"/"+parts[1], parts[3])
+		return
+	case len(parts) != 5:
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	number, err := strconv.Atoi(parts[3])
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	issue, ok := s.find(parts[0]+"/"+parts[1], number)
+	if !ok || (parts[2] == "pulls" && !issue.PullRequest) {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch parts[2] + "/" + parts[4] {
+	case "issues/comments":
+		since, _ := time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+		comments := lo.Filter(issue.Comments, func(c Comment, _ int) bool {
+			return !c.CreatedAt.Before(since)
+		})
+		writeJSON(w, http.StatusOK, lo.Map(paginate(w, r, comments), func(c Comment, _ int) *github.IssueComment {
+			return &github.IssueComment{
+				ID:        github.Int64(c.ID),
+				User:      newUser(c.Author),
+				CreatedAt: &github.Timestamp{Time: c.CreatedAt},
+				UpdatedAt: &github.Timestamp{Time: c.CreatedAt},
+				HTMLURL:   github.String(fmt.Sprintf("%s#issuecomment-%d", htmlURL(issue), c.ID)),
+				IssueURL:  github.String(s.apiURL("repos/%s/issues/%d", issue.Repo, issue.Number)),
+			}
+		}))
+	case "pulls/merge":
+		if issue.MergedAt == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case "pulls/reviews":
+		writeJSON(w, http.StatusOK, lo.Map(paginate(w, r, issue.Reviews), func(review Review, _ int) *github.PullRequestReview {
+			return &github.PullRequestReview{
+				ID:          github.Int64(review.ID),
+				User:        newUser(review.Author),
+				State:       github.String(review.State),
+				SubmittedAt: &github.Timestamp{Time: review.SubmittedAt},
+				HTMLURL:     github.String(fmt.Sprintf("%s#pullrequestreview-%d", htmlURL(issue), review.ID)),
+			}
+		}))
+	case "pulls/comments":
+		since, _ := time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+		comments := lo.Filter(issue.ReviewComments, func(c Comment, _ int) bool {
+			return !c.CreatedAt.Before(since)
+		})
+		writeJSON(w, http.StatusOK, lo.Map(paginate(w, r, comments), func(c Comment, _ int) *github.PullRequestComment {
+			return &github.PullRequestComment{
+				ID:        github.Int64(c.ID),
+				User:      newUser(c.Author),
+				CreatedAt: &github.Timestamp{Time: c.CreatedAt},
+				UpdatedAt: &github.Timestamp{Time: c.CreatedAt},
+				HTMLURL:   github.String(fmt.Sprintf("%s#discussion_r%d", htmlURL(issue), c.ID)),
+			}
+		}))
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// handleOrgs routes /orgs/{org}/teams/{slug}/{members,teams}.
+func (s *Server) handleOrgs(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/orgs/"), "/"), "/")
+	if len(parts) != 4 || parts[1] != "teams" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	org, slug := parts[0], parts[2]
+
+	s.mu.Lock()
+	team, ok := lo.Find(s.teams, func(t *Team) bool {
+		return strings.EqualFold(t.Org, org) && t.Slug == slug
+	})
+	children := lo.Filter(s.teams, func(t *Team, _ int) bool {
+		return strings.EqualFold(t.Org, org) && t.Parent == slug
+	})
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch parts[3] {
+	case "members":
+		writeJSON(w, http.StatusOK, lo.Map(paginate(w, r, team.Members), func(login string, _ int) *github.User {
+			return newUser(login)
+		}))
+	case "teams":
+		writeJSON(w, http.StatusOK, lo.Map(paginate(w, r, children), func(t *Team, _ int) *github.Team {
+			return &github.Team{
+				Name: github.String(t.Slug),
+				Slug: github.String(t.Slug),
+			}
+		}))
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// handleReleases lists the repository's releases, newest first.
+func (s *Server) handleReleases(w http.ResponseWriter, r *http.Request, repo string) {
+	s.mu.Lock()
+	releases := lo.Filter(s.releases, func(release *Release, _ int) bool {
+		return strings.EqualFold(release.Repo, repo) && !release.TagOnly
+	})
+	s.mu.Unlock()
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].PublishedAt.After(releases[j].PublishedAt)
+	})
+
+	writeJSON(w, http.StatusOK, lo.Map(paginate(w, r, releases), func(release *Release, _ int) *github.RepositoryRelease {
+		out := &github.RepositoryRelease{
+			TagName: github.String(release.Tag),
+			Name:    github.String(release.Name),
+			Draft:   github.Bool(release.Draft),
+			HTMLURL: github.String(fmt.Sprintf("https://github.com/%s/releases/tag/%s", release.Repo, release.Tag)),
+		}
+		if !release.Draft {
+			out.PublishedAt = &github.Timestamp{Time: release.PublishedAt}
+		}
+		return out
+	}))
+}
+
+// handleTags lists the tags of the repository's releases, newest first. Drafts
+// aren't tagged until they're published.
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request, repo string) {
+	s.mu.Lock()
+	tags := lo.Filter(s.releases, func(release *Release, _ int) bool {
+		return strings.EqualFold(release.Repo, repo) && !release.Draft
+	})
+	s.mu.Unlock()
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].PublishedAt.After(tags[j].PublishedAt)
+	})
+
+	writeJSON(w, http.StatusOK, lo.Map(paginate(w, r, tags), func(release *Release, _ int) *github.RepositoryTag {
+		return &github.RepositoryTag{
+			Name:   github.String(release.Tag),
+			Commit: &github.Commit{SHA: github.String(release.head())},
+		}
+	}))
+}
+
+// handleCommit gets the commit tagged by one of the repository's releases.
+func (s *Server) handleCommit(w http.ResponseWriter, repo string, sha string) {
+	s.mu.Lock()
+	release, ok := lo.Find(s.releases, func(release *Release) bool {
+		return strings.EqualFold(release.Repo, repo) && len(sha) > 0 && release.head() == sha
+	})
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &github.RepositoryCommit{
+		SHA:     github.String(sha),
+		HTMLURL: github.String(fmt.Sprintf("https://github.com/%s/commit/%s", release.Repo, sha)),
+		Commit: &github.Commit{
+			SHA:       github.String(sha),
+			Committer: &github.CommitAuthor{Date: &github.Timestamp{Time: release.PublishedAt}},
+		},
+	})
+}
+
+// head is the SHA of the tagged commit.
+func (release *Release) head() string {
+	if len(release.Commits) == 0 {
+		return ""
+	}
+	return release.Commits[len(release.Commits)-1]
+}
+
+// handleCompare compares a commit to a release's tag. The tag is ahead of the
+// commit if the release contains it, and has diverged from it otherwise.
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request, repo string, basehead string) {
+	base, head, ok := strings.Cut(basehead, "...")
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.mu.Lock()
+	release, ok := lo.Find(s.releases, func(release *Release) bool {
+		return strings.EqualFold(release.Repo, repo) && release.Tag == head
+	})
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	status := "diverged"
+	if lo.Contains(release.Commits, base) {
+		status = "ahead"
+	}
+	writeJSON(w, http.StatusOK, &github.CommitsComparison{
+		Status: github.String(status),
+	})
+}
+
+// handleGraphQL answers the pull request details query, looking up the pull
+// requests by the owner, name, and number variables of each alias.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	data := make(map[string]any)
+	var errs []map[string]string
+	for i := 0; ; i++ {
+		number, ok := req.Variables[fmt.Sprintf("n%d", i)].(float64)
+		if !ok {
+			break
+		}
+
+		alias := fmt.Sprintf("pr%d", i)
+		repo := fmt.Sprintf("%v/%v", req.Variables[fmt.Sprintf("o%d", i)], req.Variables[fmt.Sprintf("r%d", i)])
+		issue, ok := s.find(repo, int(number))
+		if !ok || !issue.PullRequest {
+			data[alias] = nil
+			errs = append(errs, map[string]string{
+				"type":    "NOT_FOUND",
+				"message": fmt.Sprintf("Could not resolve to a PullRequest with the number of %d.", int(number)),
+			})
+			continue
+		}
+
+		data[alias] = map[string]any{
+			"pullRequest": map[string]any{
+				"merged":         issue.MergedAt != nil,
+				"mergedAt":       issue.MergedAt,
+				"reviewDecision": issue.ReviewDecision,
+				"additions":      issue.Additions,
+				"deletions":      issue.Deletions,
+				"mergeCommit":    mergeCommit(issue),
+				"labels": map[string]any{
+					"nodes": lo.Map(issue.Labels, func(name string, _ int) map[string]string {
+						return map[string]string{"name": name}
+					}),
+				},
+			},
+		}
+	}
+
+	res := map[string]any{"data": data}
+	if len(errs) > 0 {
+		res["errors"] = errs
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func mergeCommit(issue *Issue) map[string]string {
+	if issue.MergedAt == nil || len(issue.MergeCommitSHA) == 0 {
+		return nil
+	}
+
+	return map[string]string{"oid": issue.MergeCommitSHA}
+}
+
+func (s *Server) find(repo string, number int) (*Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return lo.Find(s.issues, func(issue *Issue) bool {
+		return strings.EqualFold(issue.Repo, repo) && issue.Number == number
+	})
+}
+
+func (s *Server) apiURL(format string, args ...any) string {
+	return s.URL + "/" + fmt.Sprintf(format, args...)
+}
+
+func (s *Server) newIssue(issue *Issue) *github.Issue {
+	var closedAt *github.Timestamp
+	if issue.ClosedAt != nil {
+		closedAt = &github.Timestamp{Time: *issue.ClosedAt}
+	}
+
+	out := &github.Issue{
+		Number:        github.Int(issue.Number),
+		Title:         github.String(issue.Title),
+		State:         github.String(lo.Ternary(len(issue.State) > 0, issue.State, "open")),
+		User:          newUser(issue.Author),
+		Comments:      github.Int(len(issue.Comments)),
+		CreatedAt:     &github.Timestamp{Time: issue.CreatedAt},
+		UpdatedAt:     &github.Timestamp{Time: issue.UpdatedAt},
+		ClosedAt:      closedAt,
+		URL:           github.String(s.apiURL("repos/%s/issues/%d", issue.Repo, issue.Number)),
+		RepositoryURL: github.String(s.apiURL("repos/%s", issue.Repo)),
+		HTMLURL:       github.String(htmlURL(issue)),
+		Labels: lo.Map(issue.Labels, func(name string, _ int) *github.Label {
+			return &github.Label{Name: github.String(name)}
+		}),
+		Reactions: &github.Reactions{
+			TotalCount: github.Int(lo.Sum(lo.Values(issue.Reactions))),
+			PlusOne:    github.Int(issue.Reactions["+1"]),
+			MinusOne:   github.Int(issue.Reactions["-1"]),
+			Laugh:      github.Int(issue.Reactions["laugh"]),
+			Confused:   github.Int(issue.Reactions["confused"]),
+			Heart:      github.Int(issue.Reactions["heart"]),
+			Hooray:     github.Int(issue.Reactions["hooray"]),
+			Rocket:     github.Int(issue.Reactions["rocket"]),
+			Eyes:       github.Int(issue.Reactions["eyes"]),
+		},
+	}
+	if issue.PullRequest {
+		out.PullRequestLinks = &github.PullRequestLinks{
+			URL:     github.String(s.apiURL("repos/%s/pulls/%d", issue.Repo, issue.Number)),
+			HTMLURL: github.String(htmlURL(issue)),
+		}
+	}
+
+	return out
+}
+
+func htmlURL(issue *Issue) string {
+	kind := "issues"
+	if issue.PullRequest {
+		kind = "pull"
+	}
+
+	return fmt.Sprintf("https://github.com/%s/%s/%d", issue.Repo, kind, issue.Number)
+}
+
+func newUser(login string) *github.User {
+	userType := "User"
+	if strings.HasSuffix(login, "[bot]") {
+		userType = "Bot"
+	}
+
+	return &github.User{
+		Login: github.String(login),
+		Type:  github.String(userType),
+	}
+}
+
+// paginate returns the page of items that was asked for, and links to the next
+// and last pages like the API does.
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) []T {
+	query := r.URL.Query()
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 30
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	last := (len(items) + perPage - 1) / perPage
+	if page < last {
+		link := func(page int, rel string) string {
+			query.Set("page", strconv.Itoa(page))
+			u := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path, RawQuery: query.Encode()}
+			return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+		}
+		w.Header().Set("Link", link(page+1, "next")+", "+link(last, "last"))
+	}
+
+	start := (page - 1) * perPage
+	if start >= len(items) {
+		return nil
+	}
+
+	return items[start:lo.Min([]int{start + perPage, len(items)})]
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
@@ -0,0 +1,239 @@
+package fakegithub_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/fakegithub"
+	"github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC)
+
+func newServer(t *testing.T) (*fakegithub.Server, *github.Client) {
+	s := fakegithub.New("octocat")
+	t.Cleanup(s.Close)
+
+	mergedAt := start.Add(50 * time.Hour)
+	s.AddIssues(
+		&fakegithub.Issue{
+			Repo:        "octo/widgets",
+			Number:      12,
+			Title:       "Add sprockets",
+			Author:      "octocat",
+			State:       "closed",
+			CreatedAt:   start.Add(time.Hour),
+			UpdatedAt:   mergedAt,
+			ClosedAt:    &mergedAt,
+			PullRequest: true,
+			MergedAt:    &mergedAt,
+			Reviews: []fakegithub.Review{
+				{ID: 1, Author: "hubot", State: "APPROVED", SubmittedAt: start.Add(49 * time.Hour)},
+			},
+		},
+		&fakegithub.Issue{
+			Repo:      "octo/widgets",
+			Number:    13,
+			Title:     "Sprockets are too loud",
+			Author:    "octocat",
+			CreatedAt: start.Add(2 * time.Hour),
+			UpdatedAt: start.Add(3 * time.Hour),
+			Comments: []fakegithub.Comment{
+				{ID: 1, Author: "hubot", CreatedAt: start.Add(3 * time.Hour)},
+			},
+		},
+		&fakegithub.Issue{
+			Repo:      "octo/gadgets",
+			Number:    1,
+			Title:     "Gadgets are too quiet",
+			Author:    "hubot",
+			CreatedAt: start.AddDate(0, 0, -30),
+			UpdatedAt: start.AddDate(0, 0, -30),
+		},
+	)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(s.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+
+	return s, client
+}
+
+func TestSearchIssues(t *testing.T) {
+	t.Parallel()
+
+	_, client := newServer(t)
+	ctx := context.Background()
+	window := " updated:2026-10-05T00:00:00Z..2026-10-18T23:59:59Z"
+
+	for _, tc := range []struct {
+		query string
+		want  []int
+	}{
+		{query: "author:octocat" + window, want: []int{13, 12}},
+		{query: "author:octocat is:pr" + window, want: []int{12}},
+		{query: "commenter:hubot" + window, want: []int{13}},
+		{query: "reviewed-by:hubot -author:hubot" + window, want: []int{12}},
+		{query: "org:octo -repo:octo/widgets", want: []int{1}},
+		{query: "author:hubot" + window, want: nil},
+	} {
+		res, _, err := client.Search.Issues(ctx, tc.query, &github.SearchOptions{Sort: "updated", Order: "asc"})
+		require.NoError(t, err, tc.query)
+
+		var got []int
+		for _, issue := range res.Issues {
+			got = append(got, issue.GetNumber())
+		}
+		assert.Equal(t, tc.want, got, tc.query)
+		assert.Equal(t, len(tc.want), res.GetTotal(), tc.query)
+	}
+
+	_, _, err := client.Search.Issues(ctx, "label:bug", nil)
+	assert.Error(t, err)
+}
+
+func TestPagination(t *testing.T) {
+	t.Parallel()
+
+	_, client := newServer(t)
+	res, resp, err := client.Search.Issues(context.Background(), "org:octo", &github.SearchOptions{
+		ListOptions: github.ListOptions{PerPage: 2},
+	})
+	require.NoError(t, err)
+	assert.Len(t, res.Issues, 2)
+	assert.Equal(t, 3, res.GetTotal())
+	assert.Equal(t, 2, resp.NextPage)
+	assert.Equal(t, 2, resp.LastPage)
+
+	res, resp, err = client.Search.Issues(context.Background(), "org:octo", &github.SearchOptions{
+		ListOptions: github.ListOptions{PerPage: 2, Page: 2},
+	})
+	require.NoError(t, err)
+	assert.Len(t, res.Issues, 1)
+	assert.Equal(t, 0, resp.NextPage)
+}
+
+func TestPullRequests(t *testing.T) {
+	t.Parallel()
+
+	_, client := newServer(t)
+	ctx := context.Background()
+
+	merged, _, err := client.PullRequests.IsMerged(ctx, "octo", "widgets", 12)
+	require.NoError(t, err)
+	assert.True(t, merged)
+
+	reviews, _, err := client.PullRequests.ListReviews(ctx, "octo", "widgets", 12, nil)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "APPROVED", reviews[0].GetState())
+	assert.Equal(t, "hubot", reviews[0].GetUser().GetLogin())
+
+	comments, _, err := client.Issues.ListComments(ctx, "octo", "widgets", 13, &github.IssueListCommentsOptions{
+		Since: &start,
+	})
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "https://github.com/octo/widgets/issues/13#issuecomment-1", comments[0].GetHTMLURL())
+
+	_, _, err = client.PullRequests.ListReviews(ctx, "octo", "widgets", 13, nil)
+	assert.Error(t, err)
+}
+
+func TestReleases(t *testing.T) {
+	t.Parallel()
+
+	s, client := newServer(t)
+	s.AddReleases(
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.0.0", PublishedAt: start, Commits: []string{"a"}},
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.1.0", PublishedAt: start.Add(time.Hour), Commits: []string{"a", "b"}},
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.1.1", PublishedAt: start.Add(2 * time.Hour), TagOnly: true,
+			Commits: []string{"a", "b", "c"}},
+		&fakegithub.Release{Repo: "octo/gadgets", Tag: "v2.0.0", PublishedAt: start},
+	)
+	ctx := context.Background()
+
+	releases, _, err := client.Repositories.ListReleases(ctx, "octo", "widgets", nil)
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	assert.Equal(t, "v1.1.0", releases[0].GetTagName())
+	assert.Equal(t, "https://github.com/octo/widgets/releases/tag/v1.1.0", releases[0].GetHTMLURL())
+
+	tags, _, err := client.Repositories.ListTags(ctx, "octo", "widgets", nil)
+	require.NoError(t, err)
+	require.Len(t, tags, 3)
+	assert.Equal(t, "v1.1.1", tags[0].GetName())
+	assert.Equal(t, "c", tags[0].GetCommit().GetSHA())
+
+	commit, _, err := client.Repositories.GetCommit(ctx, "octo", "widgets", "c", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/octo/widgets/commit/c", commit.GetHTMLURL())
+	assert.True(t, commit.GetCommit().GetCommitter().GetDate().Time.Equal(start.Add(2*time.Hour)))
+
+	comparison, _, err := client.Repositories.CompareCommits(ctx, "octo", "widgets", "b", "v1.1.0", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ahead", comparison.GetStatus())
+
+	comparison, _, err = client.Repositories.CompareCommits(ctx, "octo", "widgets", "b", "v1.0.0", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "diverged", comparison.GetStatus())
+
+	_, _, err = client.Repositories.CompareCommits(ctx, "octo", "widgets", "b", "v9.9.9", nil)
+	assert.Error(t, err)
+}
+
+func TestTeams(t *testing.T) {
+	t.Parallel()
+
+	s, client := newServer(t)
+	s.AddTeams(
+		&fakegithub.Team{Org: "octo", Slug: "eng", Members: []string{"octocat", "dependabot[bot]"}},
+		&fakegithub.Team{Org: "octo", Slug: "widgets", Parent: "eng", Members: []string{"hubot"}},
+	)
+	ctx := context.Background()
+
+	members, _, err := client.Teams.ListTeamMembersBySlug(ctx, "octo", "eng", nil)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "octocat", members[0].GetLogin())
+	assert.Equal(t, "Bot", members[1].GetType())
+
+	children, _, err := client.Teams.ListChildTeamsByParentSlug(ctx, "octo", "eng", nil)
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+	assert.Equal(t, "widgets", children[0].GetSlug())
+
+	_, _, err = client.Teams.ListTeamMembersBySlug(ctx, "octo", "missing", nil)
+	assert.Error(t, err)
+}
+
+func TestInjectedFailures(t *testing.T) {
+	t.Parallel()
+
+	s, client := newServer(t)
+	ctx := context.Background()
+
+	s.InjectRateLimit(1)
+	_, _, err := client.Users.Get(ctx, "")
+	var rateLimitErr *github.RateLimitError
+	assert.ErrorAs(t, err, &rateLimitErr)
+
+	s.InjectAbuse(1, 0)
+	_, _, err = client.Users.Get(ctx, "")
+	var abuseErr *github.AbuseRateLimitError
+	assert.ErrorAs(t, err, &abuseErr)
+
+	user, _, err := client.Users.Get(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", user.GetLogin())
+	assert.Equal(t, 3, s.Requests())
+
+	limits, _, err := client.RateLimits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5000-4, limits.GetCore().Remaining)
+}
@@ -0,0 +1,109 @@
+package fakegithub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// parseQuery turns the issue search query into a filter. Only the qualifiers that
+// snippets uses are supported, and anything else is an error so that tests can't
+// silently pass with a query that the fake doesn't understand.
+func parseQuery(q string) (func(issue *Issue) bool, error) {
+	var filters []func(issue *Issue) bool
+	for _, term := range strings.Fields(q) {
+		negate := strings.HasPrefix(term, "-")
+		qualifier, value, ok := strings.Cut(strings.TrimPrefix(term, "-"), ":")
+		if !ok {
+			return nil, fmt.Errorf("unsupported search term `%s`", term)
+		}
+
+		var filter func(issue *Issue) bool
+		switch qualifier {
+		case "author":
+			filter = func(issue *Issue) bool {
+				return strings.EqualFold(issue.Author, value)
+			}
+		case "commenter":
+			filter = func(issue *Issue) bool {
+				return lo.ContainsBy(issue.Comments, func(c Comment) bool {
+					return strings.EqualFold(c.Author, value)
+				})
+			}
+		case "reviewed-by":
+			filter = func(issue *Issue) bool {
+				return lo.ContainsBy(issue.Reviews, func(r Review) bool {
+					return strings.EqualFold(r.Author, value)
+				})
+			}
+		case "is":
+			switch value {
+			case "pr":
+				filter = func(issue *Issue) bool { return issue.PullRequest }
+			case "issue":
+				filter = func(issue *Issue) bool { return !issue.PullRequest }
+			default:
+				return nil, fmt.Errorf("unsupported search term `%s`", term)
+			}
+		case "repo":
+			filter = func(issue *Issue) bool {
+				return strings.EqualFold(issue.Repo, value)
+			}
+		case "org":
+			filter = func(issue *Issue) bool {
+				owner, _, _ := strings.Cut(issue.Repo, "/")
+				return strings.EqualFold(owner, value)
+			}
+		case "created", "updated":
+			start, end, err := parseRange(value)
+			if err != nil {
+				return nil, fmt.Errorf("search term `%s`: %w", term, err)
+			}
+			field := qualifier
+			filter = func(issue *Issue) bool {
+				t := issue.CreatedAt
+				if field == "updated" {
+					t = issue.UpdatedAt
+				}
+				return !t.Before(start) && !t.After(end)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported search qualifier `%s`", qualifier)
+		}
+
+		if negate {
+			inner := filter
+			filter = func(issue *Issue) bool { return !inner(issue) }
+		}
+		filters = append(filters, filter)
+	}
+
+	return func(issue *Issue) bool {
+		return lo.EveryBy(filters, func(filter func(issue *Issue) bool) bool {
+			return filter(issue)
+		})
+	}, nil
+}
+
+// parseRange parses an inclusive range of ISO 8601 datetimes, i.e.
+// "2026-10-01T00:00:00Z..2026-10-14T23:59:59Z".
+func parseRange(value string) (time.Time, time.Time, error) {
+	startValue, endValue, ok := strings.Cut(value, "..")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("unsupported range `%s`", value)
+	}
+
+	start, err := time.Parse(time.RFC3339, startValue)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	end, err := time.Parse(time.RFC3339, endValue)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return start, end, nil
+}
@@ -1,24 +1,48 @@
 package format
 
 import (
+	"math"
 	"time"
 )
 
+// DurationAsAdj describes how often a report covering the duration would be
+// written, i.e. "weekly" for 7 days. Durations are rounded to the nearest day so
+// that calendar periods spanning a daylight savings change are still matched.
 func DurationAsAdj(d time.Duration) string {
+	days := time.Duration(math.Round(d.Hours()/24)) * oneDay
+
 	switch {
-	case d > oneYear:
+	case days >= oneYear:
 		return "yearly"
-	case d > oneMonth:
+	case days >= oneQuarter:
+		return "quarterly"
+	case days >= oneMonth:
 		return "monthly"
-	case d > 2*oneWeek:
+	case days >= 2*oneWeek:
 		return "biweekly"
-	case d > oneWeek:
+	case days >= oneWeek:
 		return "weekly"
-	case d > oneDay:
+	case days >= oneDay:
 		return "daily"
-	case d > time.Hour:
+	case d >= time.Hour:
 		return "hourly"
 	}
 
 	return ""
 }
+
+// DurationAsLength describes the length of a report window in whole weeks or days,
+// i.e. "2 weeks" or "10 days". Durations are rounded to the nearest day like
+// DurationAsAdj, and anything shorter is described in hours.
+func DurationAsLength(d time.Duration) string {
+	days := int(math.Round(d.Hours() / 24))
+
+	switch {
+	case days >= 7 && days%7 == 0:
+		return plural(days/7, "week", "weeks")
+	case days >= 1:
+		return plural(days, "day", "days")
+	}
+
+	return plural(int(math.Round(d.Hours())), "hour", "hours")
+}
@@ -0,0 +1,39 @@
+package format_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationAsAdj(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+
+	assert.Equal(t, "hourly", format.DurationAsAdj(2*time.Hour))
+	assert.Equal(t, "daily", format.DurationAsAdj(day))
+	assert.Equal(t, "weekly", format.DurationAsAdj(7*day))
+	// Calendar weeks that cross a daylight savings transition are an hour short.
+	assert.Equal(t, "weekly", format.DurationAsAdj(7*day-time.Hour))
+	assert.Equal(t, "biweekly", format.DurationAsAdj(14*day))
+	assert.Equal(t, "biweekly", format.DurationAsAdj(28*day))
+	assert.Equal(t, "monthly", format.DurationAsAdj(30*day))
+	assert.Equal(t, "monthly", format.DurationAsAdj(31*day))
+	assert.Equal(t, "quarterly", format.DurationAsAdj(92*day))
+	assert.Equal(t, "yearly", format.DurationAsAdj(366*day))
+}
+
+func TestDurationAsLength(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+
+	assert.Equal(t, "3 hours", format.DurationAsLength(3*time.Hour))
+	assert.Equal(t, "1 day", format.DurationAsLength(day))
+	assert.Equal(t, "1 week", format.DurationAsLength(7*day-time.Hour))
+	assert.Equal(t, "2 weeks", format.DurationAsLength(14*day))
+	assert.Equal(t, "31 days", format.DurationAsLength(31*day))
+}
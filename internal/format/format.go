@@ -1,10 +1,26 @@
 package format
 
-import "time"
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+var clk = clock.New()
+
+// Set the clock to something else, say the mock clock, for tests.
+func SetClock(c clock.Clock) {
+	clk = c
+}
 
 var (
 	oneDay   = time.Hour * 24
 	oneWeek  = oneDay * 7
 	oneMonth = oneDay * 30
-	oneYear  = oneDay * 365
+	// The shortest quarter is February through April in a non-leap year.
+	oneQuarter = oneDay * 89
+	oneYear    = oneDay * 365
 )
+
+// highlightStar marks the items that the user starred as highlights.
+const highlightStar = "⭐"
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -41,8 +42,26 @@ var (
 )
 
 type GitHubIssue struct {
-	Merged bool
-	Issue  *github.Issue
+	Merged   bool
+	MergedAt *time.Time
+	// Whether the pull request's reviews allow it to be merged, i.e. "APPROVED".
+	ReviewDecision string
+	Additions      int
+	Deletions      int
+	// SHA of the commit that the pull request was merged as.
+	MergeCommitSHA string
+	// Names of the pull request's labels. If nil, the labels from the issue are
+	// used instead.
+	Labels []string
+	// Full name of the repository that the issue belongs to, i.e. "owner/name".
+	Repository string
+	// How the user reviewed the pull request, if they reviewed it at all.
+	Review *Review
+	// Number of comments that the user left within the report window.
+	Comments int
+	// Link to the user's first comment within the report window.
+	FirstCommentURL string
+	Issue           *github.Issue
 }
 
 type CompletedIssue struct {
@@ -51,7 +70,12 @@ type CompletedIssue struct {
 	Status    string
 	Title     string
 	Duration  string
+	Review    string
+	Comments  string
 	Reactions string
+	Diffstat  string
+	Shipped   string
+	Note      string
 }
 
 type completedIssueWidths struct {
@@ -132,42 +156,108 @@ func (ci *CompletedIssue) Format(params completedIssueWidths) string {
 	}
 	buf.WriteString(" - ")
 	buf.WriteString(ci.Title)
+	if len(ci.Review) > 0 {
+		buf.WriteString(" [")
+		buf.WriteString(ci.Review)
+		buf.WriteRune(']')
+	}
+	if len(ci.Comments) > 0 {
+		buf.WriteString(" [")
+		buf.WriteString(ci.Comments)
+		buf.WriteRune(']')
+	}
 	if len(ci.Reactions) > 0 {
 		buf.WriteRune(' ')
 		buf.WriteString(ci.Reactions)
 	}
+	if len(ci.Diffstat) > 0 {
+		buf.WriteString(" (")
+		buf.WriteString(ci.Diffstat)
+		buf.WriteRune(')')
+	}
+	if len(ci.Shipped) > 0 {
+		buf.WriteString(" [")
+		buf.WriteString(ci.Shipped)
+		buf.WriteRune(']')
+	}
+	if len(ci.Note) > 0 {
+		buf.WriteString(" — ")
+		buf.WriteString(ci.Note)
+	}
 
 	return buf.String()
 }
 
-func ParseCompleted(ghi *GitHubIssue) *CompletedIssue {
-	// TODO: if only limited to 1 repo, then don't print
-	issue := ghi.Issue
+// ParseCompleted prepares the item for display. The repository is only shown
+// if showRepo is true.
+func ParseCompleted(item *Item, showRepo bool) *CompletedIssue {
+	title := item.Title
+	if item.Highlight {
+		title = highlightStar + " " + title
+	}
+
+	var diffstat string
+	if item.Type == ItemTypeCommit {
+		diffstat = fmtDiffstat(item.Additions, item.Deletions)
+	}
+
 	return &CompletedIssue{
-		Type:      fmtType(issue),
-		ID:        styleNumber.Render(fmt.Sprintf("#%d", issue.GetNumber())),
-		Status:    fmtStatus(ghi),
-		Title:     issue.GetTitle(),
-		Duration:  fmtDuration(issue),
-		Reactions: fmtReactions(issue.GetReactions()),
+		Type:      fmtType(item),
+		ID:        styleNumber.Render(Ref(item, showRepo)),
+		Status:    item.Status,
+		Title:     title,
+		Duration:  item.Duration,
+		Review:    fmtReview(item.Review),
+		Comments:  fmtActivity(item),
+		Reactions: fmtReactions(item.Reactions),
+		Diffstat:  diffstat,
+		Shipped:   fmtShipped(item),
+		Note:      item.Note,
 	}
 }
 
-func ParseAllCompleted(issues []*GitHubIssue) []*CompletedIssue {
-	return lo.Map(issues, func(ghi *GitHubIssue, _ int) *CompletedIssue {
-		return ParseCompleted(ghi)
+func ParseAllCompleted(items []*Item, showRepo bool) []*CompletedIssue {
+	return lo.Map(items, func(item *Item, _ int) *CompletedIssue {
+		return ParseCompleted(item, showRepo)
 	})
 }
 
-func FormatSection(title string, issues []*GitHubIssue) string {
-	if len(issues) == 0 {
+// RenderText renders the report for display in a terminal.
+func RenderText(r *Report) string {
+	var report bytes.Buffer
+	// weekly report for username: YYYY-mm-dd
+	report.WriteString(fmt.Sprintf("# %s report for %s: %s\n\n",
+		r.Window.Period,
+		r.User,
+		r.Window.Start.Format("2006-01-02")))
+
+	if len(r.Summary) > 0 {
+		report.WriteString(r.Summary)
+		report.WriteString("\n\n")
+	}
+
+	for _, section := range r.Sections {
+		report.WriteString(FormatSection(section, !r.SingleRepository()))
+	}
+
+	return report.String()
+}
+
+func FormatSection(s *Section, showRepo bool) string {
+	return formatSection(s, showRepo, "## ")
+}
+
+// formatSection formats the section under a heading of the level, i.e. "### "
+// for a person's section in a rollup.
+func formatSection(s *Section, showRepo bool, heading string) string {
+	if len(s.Items) == 0 {
 		return ""
 	}
 
 	var section bytes.Buffer
-	updatedIssues := ParseAllCompleted(issues)
-	section.WriteString("## ")
-	section.WriteString(title)
+	updatedIssues := ParseAllCompleted(sortByRepository(s.Items, showRepo), showRepo)
+	section.WriteString(heading)
+	section.WriteString(s.Title)
 	section.WriteString("\n\n")
 	updatedIssuesParams := GetCompletedIssueParams(updatedIssues)
 	for _, issue := range updatedIssues {
@@ -207,6 +297,127 @@ func fmtStatus(ghi *GitHubIssue) string {
 	return status
 }
 
+// Ref formats the GitHub shorthand reference for the item, i.e. "#12" or
+// "owner/repo#12" if the repository is shown. Commits are referenced by their
+// short SHA, i.e. "abc1234" or "owner/repo@abc1234", releases by their tag, and
+// Slack messages by their channel, i.e. "#general".
+func Ref(item *Item, showRepo bool) string {
+	if item.Type == ItemTypeMessage {
+		return "#" + item.Channel
+	}
+
+	if item.Type == ItemTypeRelease && item.Release != nil {
+		if showRepo {
+			return fmt.Sprintf("%s@%s", item.Repository, item.Release.Tag)
+		}
+		return item.Release.Tag
+	}
+
+	if item.Type == ItemTypeCommit {
+		if showRepo {
+			return fmt.Sprintf("%s@%s", item.Repository, shortSHA(item.SHA))
+		}
+		return shortSHA(item.SHA)
+	}
+
+	if showRepo {
+		return fmt.Sprintf("%s#%d", item.Repository, item.Number)
+	}
+
+	return fmt.Sprintf("#%d", item.Number)
+}
+
+// Kind names the type of the item, i.e. "PR", "Issue", "Commit", "Release", or
+// "Message".
+func Kind(item *Item) string {
+	switch item.Type {
+	case ItemTypePullRequest:
+		return "PR"
+	case ItemTypeCommit:
+		return "Commit"
+	case ItemTypeRelease:
+		return "Release"
+	case ItemTypeMessage:
+		return "Message"
+	}
+	return "Issue"
+}
+
+// shortSHA abbreviates the commit SHA like GitHub does.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+
+	return sha
+}
+
+// fmtDiffstat formats the lines added and deleted, i.e. "+10 -2".
+func fmtDiffstat(additions int, deletions int) string {
+	return fmt.Sprintf("+%d -%d", additions, deletions)
+}
+
+// fmtActivity describes the comments that the user left on the item, or the
+// replies to a Slack message.
+func fmtActivity(item *Item) string {
+	if item.Type == ItemTypeMessage {
+		return fmtReplies(item.Replies)
+	}
+
+	return fmtComments(item.Comments)
+}
+
+// fmtReplies formats the number of replies in a thread, i.e. "3 replies".
+func fmtReplies(count int) string {
+	if count == 0 {
+		return ""
+	}
+
+	return plural(count, "reply", "replies")
+}
+
+// fmtShipped describes the release that shipped the item, i.e. "Shipped in
+// v1.4.0", or nothing if it hasn't shipped or is a release itself.
+func fmtShipped(item *Item) string {
+	if item.Release == nil || item.Type == ItemTypeRelease {
+		return ""
+	}
+
+	return "Shipped in " + item.Release.Tag
+}
+
+// sortByRepository groups the items by repository if the repository is shown,
+// keeping the original order within each repository.
+func sortByRepository(items []*Item, showRepo bool) []*Item {
+	if !showRepo {
+		return items
+	}
+
+	sorted := append([]*Item{}, items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Repository < sorted[j].Repository
+	})
+	return sorted
+}
+
+// plural formats the count with the singular or plural form of the noun, i.e.
+// "1 comment" or "2 comments".
+func plural(count int, singular string, pluralForm string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+
+	return fmt.Sprintf("%d %s", count, pluralForm)
+}
+
+func fmtComments(count int) string {
+	if count == 0 {
+		return ""
+	}
+
+	return plural(count, "comment", "comments")
+}
+
 func fmtReaction(emoji string, count int) string {
 	if count == 0 {
 		return ""
@@ -215,37 +426,64 @@ func fmtReaction(emoji string, count int) string {
 	return fmt.Sprintf("%d %s", count, emoji)
 }
 
-func fmtReactions(reactions *github.Reactions) string {
-	content := strings.Join(lo.Filter([]string{
-		fmtReaction("❤️", reactions.GetHeart()),
-		fmtReaction("👀", reactions.GetEyes()),
-		fmtReaction("👍", reactions.GetPlusOne()),
-		fmtReaction("👎", reactions.GetMinusOne()),
-		fmtReaction("🚀", reactions.GetRocket()),
-		fmtReaction("🎉", reactions.GetHooray()),
-		fmtReaction("😃", reactions.GetLaugh()),
-		fmtReaction("😕", reactions.GetConfused()),
-	}, func(s string, _ int) bool {
-		return len(s) > 0
-	}), " ")
+// Reactions in the order that they're displayed, keyed by the GitHub reaction name.
+var reactionEmojis = []lo.Tuple2[string, string]{
+	{A: "heart", B: "❤️"},
+	{A: "eyes", B: "👀"},
+	{A: "+1", B: "👍"},
+	{A: "-1", B: "👎"},
+	{A: "rocket", B: "🚀"},
+	{A: "hooray", B: "🎉"},
+	{A: "laugh", B: "😃"},
+	{A: "confused", B: "😕"},
+}
 
-	if len(content) > 0 {
+func countReactions(reactions *github.Reactions) map[string]int {
+	return map[string]int{
+		"heart":    reactions.GetHeart(),
+		"eyes":     reactions.GetEyes(),
+		"+1":       reactions.GetPlusOne(),
+		"-1":       reactions.GetMinusOne(),
+		"rocket":   reactions.GetRocket(),
+		"hooray":   reactions.GetHooray(),
+		"laugh":    reactions.GetLaugh(),
+		"confused": reactions.GetConfused(),
+	}
+}
+
+func fmtReactions(counts map[string]int) string {
+	if content := joinReactions(counts); len(content) > 0 {
 		return fmt.Sprintf(" (%s)", content)
 	}
 
 	return ""
 }
 
-func fmtDuration(issue *github.Issue) string {
-	// rough estimates, doesn't need to be exact
-	var dur time.Duration
+// joinReactions formats the reaction counts as emoji, i.e. "2 👍 1 🚀".
+func joinReactions(counts map[string]int) string {
+	return strings.Join(lo.Filter(lo.Map(reactionEmojis, func(reaction lo.Tuple2[string, string], _ int) string {
+		return fmtReaction(reaction.B, counts[reaction.A])
+	}), func(s string, _ int) bool {
+		return len(s) > 0
+	}), " ")
+}
+
+func openDuration(issue *github.Issue) time.Duration {
 	if issue.GetState() == "closed" {
-		dur = issue.GetClosedAt().Sub(issue.GetCreatedAt().Time)
-	} else {
-		dur = time.Since(issue.CreatedAt.Time)
+		return issue.GetClosedAt().Sub(issue.GetCreatedAt().Time)
 	}
 
-	val := u.NewValue(dur.Seconds(), u.Second)
+	return clk.Since(issue.CreatedAt.Time)
+}
+
+func fmtDuration(issue *github.Issue) string {
+	return roughDuration(openDuration(issue))
+}
+
+// roughDuration rounds the duration up to the largest whole unit, i.e. "<=3 days".
+func roughDuration(d time.Duration) string {
+	// rough estimates, doesn't need to be exact
+	val := u.NewValue(d.Seconds(), u.Second)
 	var newVal u.Value
 	for _, unit := range orderedDurationUnits {
 		convertedVal := val.MustConvert(unit)
@@ -263,11 +501,18 @@ func fmtDuration(issue *github.Issue) string {
 	return fmt.Sprintf("<=%s", fmtVal)
 }
 
-func fmtType(issue *github.Issue) string {
+func fmtType(item *Item) string {
 	var label string
-	if issue.IsPullRequest() {
+	switch item.Type {
+	case ItemTypePullRequest:
 		label = "PR"
-	} else {
+	case ItemTypeCommit:
+		label = "CO"
+	case ItemTypeRelease:
+		label = "RE"
+	case ItemTypeMessage:
+		label = "SL"
+	default:
 		label = "IS"
 	}
 
@@ -0,0 +1,118 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+	`>`, `\>`,
+	`#`, `\#`,
+	`|`, `\|`,
+)
+
+// RenderMarkdown renders the report as plain Markdown without any terminal escape
+// codes, so that it can be pasted into a wiki or written to a file.
+func RenderMarkdown(r *Report) string {
+	var report bytes.Buffer
+	report.WriteString(fmt.Sprintf("# %s report for %s: %s\n\n",
+		r.Window.Period,
+		r.User,
+		r.Window.Start.Format("2006-01-02")))
+
+	if len(r.Summary) > 0 {
+		report.WriteString(r.Summary)
+		report.WriteString("\n\n")
+	}
+
+	for _, section := range r.Sections {
+		report.WriteString(FormatMarkdownSection(section, !r.SingleRepository()))
+	}
+
+	return report.String()
+}
+
+func FormatMarkdownSection(s *Section, showRepo bool) string {
+	return formatMarkdownSection(s, showRepo, "## ")
+}
+
+// formatMarkdownSection formats the section under a heading of the level, i.e.
+// "### " for a person's section in a rollup.
+func formatMarkdownSection(s *Section, showRepo bool, heading string) string {
+	if len(s.Items) == 0 {
+		return ""
+	}
+
+	var section bytes.Buffer
+	section.WriteString(heading)
+	section.WriteString(s.Title)
+	section.WriteString("\n\n")
+	for _, item := range sortByRepository(s.Items, showRepo) {
+		section.WriteString(FormatMarkdownItem(item, showRepo))
+		section.WriteRune('\n')
+	}
+	section.WriteRune('\n')
+
+	return section.String()
+}
+
+// FormatMarkdownItem formats the item as a list entry, i.e.
+// "- PR [owner/repo#12](https://...) **merged** <=3 days - Title (2 👍)". The
+// repository is only shown if showRepo is true. Highlights are starred and notes
+// are appended, i.e. "- ⭐ PR ... - Title — Unblocked the launch". Commits are
+// listed under the pull request that they belong to.
+func FormatMarkdownItem(item *Item, showRepo bool) string {
+	var buf bytes.Buffer
+	buf.WriteString("- ")
+	if item.Highlight {
+		buf.WriteString(highlightStar + " ")
+	}
+	buf.WriteString(fmt.Sprintf("%s [%s](%s)", Kind(item), Ref(item, showRepo), item.HTMLURL))
+	if len(item.Status) > 0 {
+		buf.WriteString(fmt.Sprintf(" **%s**", item.Status))
+	}
+	if len(item.Duration) > 0 {
+		buf.WriteRune(' ')
+		buf.WriteString(item.Duration)
+	}
+	buf.WriteString(" - ")
+	buf.WriteString(markdownEscaper.Replace(item.Title))
+	if item.Review != nil {
+		buf.WriteString(fmt.Sprintf(" _%s_", fmtReview(item.Review)))
+	}
+	if item.Comments > 0 {
+		if len(item.FirstCommentURL) > 0 {
+			buf.WriteString(fmt.Sprintf(" _[%s](%s)_", fmtComments(item.Comments), item.FirstCommentURL))
+		} else {
+			buf.WriteString(fmt.Sprintf(" _%s_", fmtComments(item.Comments)))
+		}
+	}
+	if item.Replies > 0 {
+		buf.WriteString(fmt.Sprintf(" _%s_", fmtReplies(item.Replies)))
+	}
+	buf.WriteString(fmtReactions(item.Reactions))
+	if item.Type == ItemTypeCommit {
+		buf.WriteString(fmt.Sprintf(" (%s)", fmtDiffstat(item.Additions, item.Deletions)))
+	}
+	if shipped := fmtShipped(item); len(shipped) > 0 {
+		buf.WriteString(fmt.Sprintf(" _[%s](%s)_", markdownEscaper.Replace(shipped), item.Release.HTMLURL))
+	}
+	if len(item.Note) > 0 {
+		buf.WriteString(" — ")
+		buf.WriteString(markdownEscaper.Replace(item.Note))
+	}
+	for _, c := range item.Commits {
+		buf.WriteString(fmt.Sprintf("\n  - [%s](%s) %s (%s)",
+			shortSHA(c.SHA), c.HTMLURL, markdownEscaper.Replace(c.Subject), fmtDiffstat(c.Additions, c.Deletions)))
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,166 @@
+package format_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+
+	out := format.RenderMarkdown(testReport())
+	assert.NotContains(t, out, "\x1b")
+
+	lines := strings.Split(out, "\n")
+	assert.Equal(t, "# biweekly report for octocat: 2026-10-05", lines[0])
+	assert.Contains(t, lines, "## Completed this cycle")
+	assert.Contains(t, lines, "- PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** <=3 days - Add sprockets (2 👍 1 🚀)")
+	assert.Contains(t, lines, "## Remaining")
+}
+
+func TestRenderMarkdownSingleRepository(t *testing.T) {
+	t.Parallel()
+
+	report := testReport()
+	report.Scope.Repos = []string{"octo/widgets"}
+
+	out := format.RenderMarkdown(report)
+	assert.Contains(t, out, "- PR [#12](https://github.com/octo/widgets/pull/12)")
+	assert.NotContains(t, out, "octo/widgets#")
+}
+
+func TestFormatMarkdownItemEscapesTitle(t *testing.T) {
+	t.Parallel()
+
+	item := &format.Item{
+		Type:       format.ItemTypeIssue,
+		Number:     3,
+		Repository: "octo/widgets",
+		Status:     "done",
+		Title:      "Fix *bold* [links] in <templates>",
+		HTMLURL:    "https://github.com/octo/widgets/issues/3",
+	}
+	assert.Equal(t,
+		`- Issue [octo/widgets#3](https://github.com/octo/widgets/issues/3) **done** - Fix \*bold\* \[links\] in \<templates\>`,
+		format.FormatMarkdownItem(item, true))
+}
+
+func TestFormatMarkdownItemWithComments(t *testing.T) {
+	t.Parallel()
+
+	item := &format.Item{
+		Type:            format.ItemTypeIssue,
+		Number:          9,
+		Repository:      "octo/gears",
+		Status:          "active",
+		Title:           "Gears grind at startup",
+		HTMLURL:         "https://github.com/octo/gears/issues/9",
+		Comments:        3,
+		FirstCommentURL: "https://github.com/octo/gears/issues/9#issuecomment-1",
+	}
+	assert.Equal(t,
+		"- Issue [octo/gears#9](https://github.com/octo/gears/issues/9) **active** - Gears grind at startup _[3 comments](https://github.com/octo/gears/issues/9#issuecomment-1)_",
+		format.FormatMarkdownItem(item, true))
+}
+
+func TestFormatMarkdownItemHighlightAndNote(t *testing.T) {
+	t.Parallel()
+
+	item := &format.Item{
+		Type:       format.ItemTypePullRequest,
+		Number:     12,
+		Repository: "octo/widgets",
+		Status:     "merged",
+		Title:      "Add sprockets",
+		HTMLURL:    "https://github.com/octo/widgets/pull/12",
+		Highlight:  true,
+		Note:       "Unblocked the *launch*",
+	}
+	assert.Equal(t,
+		`- ⭐ PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** - Add sprockets — Unblocked the \*launch\*`,
+		format.FormatMarkdownItem(item, true))
+}
+
+func TestFormatMarkdownItemCommits(t *testing.T) {
+	t.Parallel()
+
+	commit := &format.Commit{
+		SHA:       "0123456789abcdef0123456789abcdef01234567",
+		Subject:   "Fix *typo*",
+		Additions: 1,
+		Deletions: 1,
+		HTMLURL:   "https://github.com/octo/widgets/commit/0123456789abcdef0123456789abcdef01234567",
+	}
+	assert.Equal(t,
+		"- Commit [octo/widgets@0123456](https://github.com/octo/widgets/commit/0123456789abcdef0123456789abcdef01234567) - Fix \\*typo\\* (+1 -1)",
+		format.FormatMarkdownItem(format.NewCommitItem("octo/widgets", commit), true))
+	assert.Equal(t,
+		"- Commit [0123456](https://github.com/octo/widgets/commit/0123456789abcdef0123456789abcdef01234567) - Fix \\*typo\\* (+1 -1)",
+		format.FormatMarkdownItem(format.NewCommitItem("octo/widgets", commit), false))
+
+	pr := &format.Item{
+		Type:       format.ItemTypePullRequest,
+		Number:     12,
+		Repository: "octo/widgets",
+		Status:     "merged",
+		Title:      "Add sprockets",
+		HTMLURL:    "https://github.com/octo/widgets/pull/12",
+		Commits:    []*format.Commit{commit},
+	}
+	assert.Equal(t,
+		"- PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** - Add sprockets\n"+
+			"  - [0123456](https://github.com/octo/widgets/commit/0123456789abcdef0123456789abcdef01234567) Fix \\*typo\\* (+1 -1)",
+		format.FormatMarkdownItem(pr, true))
+}
+
+func TestFormatMarkdownItemRelease(t *testing.T) {
+	t.Parallel()
+
+	release := &format.Release{
+		Tag:     "v1.4.0",
+		Name:    "Widgets 1.4",
+		HTMLURL: "https://github.com/octo/widgets/releases/tag/v1.4.0",
+	}
+	pr := &format.Item{
+		Type:       format.ItemTypePullRequest,
+		Number:     12,
+		Repository: "octo/widgets",
+		Status:     "merged",
+		Title:      "Add sprockets",
+		HTMLURL:    "https://github.com/octo/widgets/pull/12",
+		Release:    release,
+	}
+	assert.Equal(t,
+		"- PR [octo/widgets#12](https://github.com/octo/widgets/pull/12) **merged** - Add sprockets _[Shipped in v1.4.0](https://github.com/octo/widgets/releases/tag/v1.4.0)_",
+		format.FormatMarkdownItem(pr, true))
+
+	assert.Equal(t,
+		"- Release [octo/widgets@v1.4.0](https://github.com/octo/widgets/releases/tag/v1.4.0) - Widgets 1.4",
+		format.FormatMarkdownItem(format.NewReleaseItem("octo/widgets", release), true))
+
+	release.Name = ""
+	assert.Equal(t,
+		"- Release [v1.4.0](https://github.com/octo/widgets/releases/tag/v1.4.0) - v1.4.0",
+		format.FormatMarkdownItem(format.NewReleaseItem("octo/widgets", release), false))
+}
+
+func TestFormatMarkdownItemMessage(t *testing.T) {
+	t.Parallel()
+
+	item := format.NewMessageItem(&format.Message{
+		Channel:   "general",
+		Text:      "Sprockets are *out*\nMore details in the thread",
+		Replies:   3,
+		Reactions: map[string]int{"hooray": 4},
+		Permalink: "https://octo.slack.com/archives/C01GENERAL/p1791644400000100",
+	})
+	assert.Equal(t,
+		"- Message [#general](https://octo.slack.com/archives/C01GENERAL/p1791644400000100) - Sprockets are \\*out\\* _3 replies_ (4 🎉)",
+		format.FormatMarkdownItem(item, true))
+
+	long := format.NewMessageItem(&format.Message{Channel: "general", Text: strings.Repeat("a", 150)})
+	assert.Equal(t, strings.Repeat("a", 99)+"…", long.Title)
+}
@@ -0,0 +1,348 @@
+package format
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+)
+
+// ReportVersion is the version of the report schema. It changes whenever a field
+// is removed or changes meaning, but not when a field is added. Version 2 added
+// commits, releases, and messages as items, which have no number.
+const ReportVersion = "2"
+
+// ReportSchema is the JSON Schema describing the JSON encoding of Report.
+//
+//go:embed report.schema.json
+var ReportSchema []byte
+
+const (
+	ItemTypeIssue       = "issue"
+	ItemTypePullRequest = "pull_request"
+	ItemTypeCommit      = "commit"
+	ItemTypeRelease     = "release"
+	ItemTypeMessage     = "message"
+)
+
+// Report is everything that was collected for a user within a window of time,
+// grouped into sections.
+type Report struct {
+	Version string `json:"version"`
+	User    string `json:"user"`
+	Window  Window `json:"window"`
+	Scope   Scope  `json:"scope"`
+	// Sentence that opens the report, i.e. "For the period of 2026-10-05 to
+	// 2026-10-18 (2 weeks), octocat filed 2 new issues."
+	Summary  string     `json:"summary,omitempty"`
+	Sections []*Section `json:"sections"`
+}
+
+// Scope is what the report was limited to. An empty scope covers everything the
+// user has access to.
+type Scope struct {
+	Repos        []string `json:"repos"`
+	Orgs         []string `json:"orgs"`
+	ExcludeRepos []string `json:"exclude_repos"`
+}
+
+// SingleRepository returns true if the report only covers a single repository, in
+// which case the repository doesn't need to be shown for each item.
+func (r *Report) SingleRepository() bool {
+	return len(r.Scope.Repos) == 1 && len(r.Scope.Orgs) == 0
+}
+
+// Window is the range of time [Start, End) covered by a report.
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// How often a report of this length would be written, i.e. "weekly".
+	Period string `json:"period"`
+}
+
+type Section struct {
+	Title string  `json:"title"`
+	Items []*Item `json:"items"`
+}
+
+// Item is a single issue, pull request, commit, release, or Slack message in the
+// report.
+type Item struct {
+	Type   string `json:"type"`
+	Number int    `json:"number"`
+	// Full SHA of the commit, if the item is for a commit.
+	SHA        string     `json:"sha,omitempty"`
+	Repository string     `json:"repository"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+	MergedAt   *time.Time `json:"merged_at,omitempty"`
+	// Rough duration that the item was open for, i.e. "<=2 weeks".
+	Duration        string `json:"duration"`
+	DurationSeconds int64  `json:"duration_seconds"`
+	// Counts of each reaction, keyed by the GitHub reaction name, i.e. "+1".
+	Reactions map[string]int `json:"reactions"`
+	HTMLURL   string         `json:"html_url"`
+	URL       string         `json:"url"`
+	// How the user reviewed the pull request, if the item is for a review.
+	Review *Review `json:"review,omitempty"`
+	// Number of comments that the user left within the report window.
+	Comments int `json:"comments"`
+	// Link to the user's first comment within the report window.
+	FirstCommentURL string   `json:"first_comment_url,omitempty"`
+	Labels          []string `json:"labels"`
+	// Whether the pull request's reviews allow it to be merged, i.e. "APPROVED".
+	ReviewDecision string `json:"review_decision,omitempty"`
+	// Lines added and deleted by the pull request or commit.
+	Additions int `json:"additions,omitempty"`
+	Deletions int `json:"deletions,omitempty"`
+	// The user's commits from local repositories that belong to the pull request.
+	Commits []*Commit `json:"commits,omitempty"`
+	// SHA of the commit that the pull request was merged as.
+	MergeCommitSHA string `json:"merge_commit_sha,omitempty"`
+	// First release that shipped the pull request, or the release itself if the
+	// item is for a release.
+	Release *Release `json:"release,omitempty"`
+	// Name of the Slack channel, if the item is for a message.
+	Channel string `json:"channel,omitempty"`
+	// Number of replies in the thread that the message started.
+	Replies int `json:"replies,omitempty"`
+	// Whether the user starred the item as a highlight of the report.
+	Highlight bool `json:"highlight,omitempty"`
+	// One-line note that the user attached to the item.
+	Note string `json:"note,omitempty"`
+}
+
+// Commit is a commit from a local repository.
+type Commit struct {
+	SHA        string    `json:"sha"`
+	Subject    string    `json:"subject"`
+	AuthoredAt time.Time `json:"authored_at"`
+	Additions  int       `json:"additions"`
+	Deletions  int       `json:"deletions"`
+	HTMLURL    string    `json:"html_url"`
+	URL        string    `json:"url"`
+}
+
+// Release is a published release of a repository.
+type Release struct {
+	Tag         string    `json:"tag"`
+	Name        string    `json:"name"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+}
+
+// NewRelease converts the GitHub release.
+func NewRelease(release *github.RepositoryRelease) *Release {
+	return &Release{
+		Tag:         release.GetTagName(),
+		Name:        release.GetName(),
+		PublishedAt: release.GetPublishedAt().Time,
+		HTMLURL:     release.GetHTMLURL(),
+	}
+}
+
+// Message is a Slack message that the user sent.
+type Message struct {
+	// Name of the channel, without the leading "#".
+	Channel string
+	Text    string
+	SentAt  time.Time
+	// Number of replies in the thread that the message started.
+	Replies int
+	// Counts of each reaction, keyed by the GitHub reaction name.
+	Reactions map[string]int
+	Permalink string
+}
+
+// NewReport creates a report with no sections for the user over [start, end).
+func NewReport(user string, start time.Time, end time.Time) *Report {
+	return &Report{
+		Version: ReportVersion,
+		User:    user,
+		Window: Window{
+			Start:  start,
+			End:    end,
+			Period: DurationAsAdj(end.Sub(start)),
+		},
+		Scope: Scope{
+			Repos:        []string{},
+			Orgs:         []string{},
+			ExcludeRepos: []string{},
+		},
+		Sections: []*Section{},
+	}
+}
+
+// Summarize sets the summary from phrases describing what the user did within the
+// window, i.e. "filed 2 new issues". Without any phrases, the summary only
+// introduces the sections.
+func (r *Report) Summarize(phrases []string) {
+	period := fmt.Sprintf("For the period of %s (%s)",
+		fmtDateRange(r.Window), DurationAsLength(r.Window.End.Sub(r.Window.Start)))
+
+	switch len(phrases) {
+	case 0:
+		if lo.SomeBy(r.Sections, func(s *Section) bool { return len(s.Items) > 0 }) {
+			r.Summary = fmt.Sprintf("%s, here's what %s worked on.", period, r.User)
+		} else {
+			r.Summary = fmt.Sprintf("%s, %s had no activity.", period, r.User)
+		}
+	case 1:
+		r.Summary = fmt.Sprintf("%s, %s %s.", period, r.User, phrases[0])
+	case 2:
+		r.Summary = fmt.Sprintf("%s, %s %s and %s.", period, r.User, phrases[0], phrases[1])
+	default:
+		r.Summary = fmt.Sprintf("%s, %s %s, and %s.", period, r.User,
+			strings.Join(phrases[:len(phrases)-1], ", "), phrases[len(phrases)-1])
+	}
+}
+
+// AddSection appends a section with the issues to the report.
+func (r *Report) AddSection(title string, issues []*GitHubIssue) {
+	r.Sections = append(r.Sections, &Section{
+		Title: title,
+		Items: lo.Map(issues, func(ghi *GitHubIssue, _ int) *Item {
+			return NewItem(ghi)
+		}),
+	})
+}
+
+// SelectSections keeps only the sections with the titles, in the order that the
+// titles are given. Titles are matched case insensitively.
+func (r *Report) SelectSections(titles []string) {
+	r.Sections = lo.FilterMap(titles, func(title string, _ int) (*Section, bool) {
+		return lo.Find(r.Sections, func(s *Section) bool {
+			return strings.EqualFold(s.Title, title)
+		})
+	})
+}
+
+// NewItem converts the issue into a report item.
+func NewItem(ghi *GitHubIssue) *Item {
+	issue := ghi.Issue
+
+	itemType := ItemTypeIssue
+	if issue.IsPullRequest() {
+		itemType = ItemTypePullRequest
+	}
+
+	var closedAt *time.Time
+	if issue.ClosedAt != nil {
+		closedAt = &issue.ClosedAt.Time
+	}
+
+	labels := ghi.Labels
+	if labels == nil {
+		labels = lo.Map(issue.Labels, func(label *github.Label, _ int) string {
+			return label.GetName()
+		})
+	}
+
+	return &Item{
+		Type:            itemType,
+		Number:          issue.GetNumber(),
+		Repository:      ghi.Repository,
+		Title:           issue.GetTitle(),
+		Status:          fmtStatus(ghi),
+		CreatedAt:       issue.GetCreatedAt().Time,
+		ClosedAt:        closedAt,
+		MergedAt:        ghi.MergedAt,
+		Duration:        fmtDuration(issue),
+		DurationSeconds: int64(openDuration(issue).Seconds()),
+		Reactions:       countReactions(issue.GetReactions()),
+		HTMLURL:         issue.GetHTMLURL(),
+		URL:             issue.GetURL(),
+		Review:          ghi.Review,
+		Comments:        ghi.Comments,
+		FirstCommentURL: ghi.FirstCommentURL,
+		Labels:          labels,
+		ReviewDecision:  ghi.ReviewDecision,
+		Additions:       ghi.Additions,
+		Deletions:       ghi.Deletions,
+		MergeCommitSHA:  ghi.MergeCommitSHA,
+	}
+}
+
+// NewCommitItem converts the commit from the repository into a report item, for
+// commits that didn't go through a pull request.
+func NewCommitItem(repository string, c *Commit) *Item {
+	return &Item{
+		Type:       ItemTypeCommit,
+		SHA:        c.SHA,
+		Repository: repository,
+		Title:      c.Subject,
+		CreatedAt:  c.AuthoredAt,
+		Reactions:  map[string]int{},
+		HTMLURL:    c.HTMLURL,
+		URL:        c.URL,
+		Labels:     []string{},
+		Additions:  c.Additions,
+		Deletions:  c.Deletions,
+	}
+}
+
+// NewReleaseItem converts the release of the repository into a report item,
+// titled with the name of the release or otherwise its tag.
+func NewReleaseItem(repository string, release *Release) *Item {
+	title := release.Name
+	if len(title) == 0 {
+		title = release.Tag
+	}
+
+	return &Item{
+		Type:       ItemTypeRelease,
+		Repository: repository,
+		Title:      title,
+		CreatedAt:  release.PublishedAt,
+		Reactions:  map[string]int{},
+		HTMLURL:    release.HTMLURL,
+		URL:        release.HTMLURL,
+		Labels:     []string{},
+		Release:    release,
+	}
+}
+
+// maxMessageTitle is the most characters of a message that are used as its title.
+const maxMessageTitle = 100
+
+// NewMessageItem converts the Slack message into a report item, titled with the
+// first line of the message.
+func NewMessageItem(m *Message) *Item {
+	title, _, _ := strings.Cut(strings.TrimSpace(m.Text), "\n")
+	if runes := []rune(title); len(runes) > maxMessageTitle {
+		title = string(runes[:maxMessageTitle-1]) + "…"
+	}
+
+	reactions := m.Reactions
+	if reactions == nil {
+		reactions = map[string]int{}
+	}
+
+	return &Item{
+		Type:      ItemTypeMessage,
+		Title:     title,
+		CreatedAt: m.SentAt,
+		Reactions: reactions,
+		HTMLURL:   m.Permalink,
+		URL:       m.Permalink,
+		Labels:    []string{},
+		Channel:   m.Channel,
+		Replies:   m.Replies,
+	}
+}
+
+// RenderJSON encodes the report as indented JSON matching ReportSchema.
+func RenderJSON(r *Report) (string, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,245 @@
+package format_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() *format.Report {
+	start := time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 14)
+	mergedAt := start.Add(50 * time.Hour)
+
+	report := format.NewReport("octocat", start, end)
+	report.AddSection("Completed this cycle", []*format.GitHubIssue{
+		{
+			Merged:         true,
+			MergedAt:       &mergedAt,
+			ReviewDecision: "APPROVED",
+			Additions:      120,
+			Deletions:      4,
+			Labels:         []string{"feature"},
+			Repository:     "octo/widgets",
+			Issue: &github.Issue{
+				Number:    github.Int(12),
+				Title:     github.String("Add sprockets"),
+				State:     github.String("closed"),
+				CreatedAt: &github.Timestamp{Time: start.Add(time.Hour)},
+				ClosedAt:  &github.Timestamp{Time: start.Add(50 * time.Hour)},
+				HTMLURL:   github.String("https://github.com/octo/widgets/pull/12"),
+				URL:       github.String("https://api.github.com/repos/octo/widgets/issues/12"),
+				PullRequestLinks: &github.PullRequestLinks{
+					URL: github.String("https://api.github.com/repos/octo/widgets/pulls/12"),
+				},
+				Reactions: &github.Reactions{
+					PlusOne: github.Int(2),
+					Rocket:  github.Int(1),
+				},
+			},
+		},
+	})
+	report.AddSection("Remaining", []*format.GitHubIssue{
+		{
+			Repository: "octo/widgets",
+			Issue: &github.Issue{
+				Number:    github.Int(13),
+				Title:     github.String("Sprockets are too loud"),
+				State:     github.String("open"),
+				CreatedAt: &github.Timestamp{Time: start.Add(2 * time.Hour)},
+				HTMLURL:   github.String("https://github.com/octo/widgets/issues/13"),
+				URL:       github.String("https://api.github.com/repos/octo/widgets/issues/13"),
+				Labels:    []*github.Label{{Name: github.String("bug")}},
+			},
+		},
+	})
+
+	return report
+}
+
+func TestNewReport(t *testing.T) {
+	t.Parallel()
+
+	report := testReport()
+	assert.Equal(t, "biweekly", report.Window.Period)
+	require.Len(t, report.Sections, 2)
+
+	merged := report.Sections[0].Items[0]
+	assert.Equal(t, format.ItemTypePullRequest, merged.Type)
+	assert.Equal(t, "merged", merged.Status)
+	assert.Equal(t, "octo/widgets", merged.Repository)
+	assert.Equal(t, int64(49*time.Hour/time.Second), merged.DurationSeconds)
+	assert.Equal(t, 2, merged.Reactions["+1"])
+	assert.Equal(t, 1, merged.Reactions["rocket"])
+	require.NotNil(t, merged.ClosedAt)
+	assert.Equal(t, merged.ClosedAt, merged.MergedAt)
+	assert.Equal(t, "APPROVED", merged.ReviewDecision)
+	assert.Equal(t, 120, merged.Additions)
+	assert.Equal(t, []string{"feature"}, merged.Labels)
+
+	open := report.Sections[1].Items[0]
+	assert.Equal(t, format.ItemTypeIssue, open.Type)
+	assert.Equal(t, "active", open.Status)
+	assert.Nil(t, open.ClosedAt)
+	assert.Equal(t, []string{"bug"}, open.Labels)
+}
+
+func TestSelectSections(t *testing.T) {
+	t.Parallel()
+
+	report := testReport()
+	report.SelectSections([]string{"remaining", "Missing", "Completed this cycle"})
+	require.Len(t, report.Sections, 2)
+	assert.Equal(t, "Remaining", report.Sections[0].Title)
+	assert.Equal(t, "Completed this cycle", report.Sections[1].Title)
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	report := format.NewReport("octocat", time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC))
+	report.Summarize(nil)
+	assert.Equal(t, "For the period of 2026-10-05 to 2026-10-18 (2 weeks), octocat had no activity.", report.Summary)
+
+	report = testReport()
+	for _, tc := range []struct {
+		phrases []string
+		want    string
+	}{
+		{want: "For the period of 2026-10-05 to 2026-10-18 (2 weeks), here's what octocat worked on."},
+		{
+			phrases: []string{"filed 1 new issue"},
+			want:    "For the period of 2026-10-05 to 2026-10-18 (2 weeks), octocat filed 1 new issue.",
+		},
+		{
+			phrases: []string{"created and merged 2 pull requests", "filed 1 new issue"},
+			want:    "For the period of 2026-10-05 to 2026-10-18 (2 weeks), octocat created and merged 2 pull requests and filed 1 new issue.",
+		},
+		{
+			phrases: []string{"created and merged 2 pull requests", "reviewed 1 pull request", "filed 1 new issue"},
+			want:    "For the period of 2026-10-05 to 2026-10-18 (2 weeks), octocat created and merged 2 pull requests, reviewed 1 pull request, and filed 1 new issue.",
+		},
+	} {
+		report.Summarize(tc.phrases)
+		assert.Equal(t, tc.want, report.Summary)
+	}
+
+	assert.Contains(t, format.RenderMarkdown(report), "\n\nFor the period of 2026-10-05 to 2026-10-18 (2 weeks), octocat created")
+}
+
+// The JSON encoding should have every field that the schema requires.
+func TestRenderJSONMatchesSchema(t *testing.T) {
+	t.Parallel()
+
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties struct {
+			Version struct {
+				Const string `json:"const"`
+			} `json:"version"`
+		} `json:"properties"`
+		Defs map[string]struct {
+			Required []string `json:"required"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(format.ReportSchema, &schema))
+	assert.Equal(t, format.ReportVersion, schema.Properties.Version.Const)
+
+	out, err := format.RenderJSON(testReport())
+	require.NoError(t, err)
+
+	var report map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	for _, key := range schema.Required {
+		assert.Contains(t, report, key)
+	}
+
+	window := report["window"].(map[string]any)
+	for _, key := range schema.Defs["window"].Required {
+		assert.Contains(t, window, key)
+	}
+
+	scope := report["scope"].(map[string]any)
+	for _, key := range schema.Defs["scope"].Required {
+		assert.Contains(t, scope, key)
+	}
+
+	for _, s := range report["sections"].([]any) {
+		section := s.(map[string]any)
+		for _, key := range schema.Defs["section"].Required {
+			assert.Contains(t, section, key)
+		}
+
+		for _, i := range section["items"].([]any) {
+			item := i.(map[string]any)
+			for _, key := range schema.Defs["item"].Required {
+				assert.Contains(t, item, key)
+			}
+		}
+	}
+}
+
+func TestRollup(t *testing.T) {
+	t.Parallel()
+
+	alice := testReport()
+	alice.User = "alice"
+	bob := format.NewReport("bob", alice.Window.Start, alice.Window.End)
+	bob.Summarize(nil)
+
+	rollup := format.NewRollup([]*format.Person{
+		{User: "alice", Totals: format.Totals{PullRequestsMerged: 1, ReviewsGiven: 2}, Report: alice},
+		{User: "bob", Totals: format.Totals{ReviewsGiven: 1, IssuesClosed: 1}, Report: bob},
+	})
+	assert.Equal(t, []string{"alice", "bob"}, rollup.Users)
+	assert.Equal(t, format.Totals{PullRequestsMerged: 1, ReviewsGiven: 3, IssuesClosed: 1}, rollup.Totals)
+	assert.Equal(t, "For the period of 2026-10-05 to 2026-10-18 (2 weeks), the team of 2 merged 1 pull request, "+
+		"gave 3 reviews, and closed 1 issue.", rollup.Summary)
+
+	out := format.RenderRollupMarkdown(rollup)
+	assert.Contains(t, out, "# biweekly team report: 2026-10-05\n\n")
+	assert.Contains(t, out, "| alice | 1 | 2 | 0 |\n| bob | 0 | 1 | 1 |\n| **Team** | 1 | 3 | 1 |\n")
+	assert.Contains(t, out, "## alice\n\n### Completed this cycle\n\n- PR [octo/widgets#12]")
+	assert.Contains(t, out, "## bob\n\nFor the period of 2026-10-05 to 2026-10-18 (2 weeks), bob had no activity.\n\n")
+
+	text := format.RenderRollupText(rollup)
+	assert.Contains(t, text, "alice  1 PR merged, 2 reviews given, 0 issues closed\n")
+	assert.Contains(t, text, "Team   1 PR merged, 3 reviews given, 1 issue closed\n")
+
+	encoded, err := format.RenderRollupJSON(rollup)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(encoded), &decoded))
+	assert.Equal(t, map[string]any{"pull_requests_merged": 1.0, "reviews_given": 3.0, "issues_closed": 1.0}, decoded["totals"])
+	assert.Len(t, decoded["people"], 2)
+
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties struct {
+			Version struct {
+				Const string `json:"const"`
+			} `json:"version"`
+		} `json:"properties"`
+		Defs map[string]struct {
+			Required []string `json:"required"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(format.RollupSchema, &schema))
+	assert.Equal(t, format.RollupVersion, schema.Properties.Version.Const)
+	assert.Equal(t, format.RollupVersion, decoded["version"])
+	for _, key := range schema.Required {
+		assert.Contains(t, decoded, key)
+	}
+	for _, p := range decoded["people"].([]any) {
+		for _, key := range schema.Defs["person"].Required {
+			assert.Contains(t, p, key)
+		}
+		assert.Equal(t, format.ReportVersion, p.(map[string]any)["report"].(map[string]any)["version"])
+	}
+}
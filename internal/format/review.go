@@ -0,0 +1,56 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v53/github"
+)
+
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewCommented        = "commented"
+)
+
+// Review summarizes how a user reviewed a pull request.
+type Review struct {
+	// One of ReviewApproved, ReviewChangesRequested, or ReviewCommented.
+	State string `json:"state"`
+	// Number of review comments left on the diff.
+	Comments int `json:"comments"`
+}
+
+// NewReview summarizes the reviews submitted by a single user, which should be in
+// the order that they were submitted. The latest approval or request for changes
+// takes precedence over any reviews that only commented.
+func NewReview(reviews []*github.PullRequestReview, comments int) *Review {
+	state := ReviewCommented
+	for _, review := range reviews {
+		switch review.GetState() {
+		case "APPROVED":
+			state = ReviewApproved
+		case "CHANGES_REQUESTED":
+			state = ReviewChangesRequested
+		}
+	}
+
+	return &Review{
+		State:    state,
+		Comments: comments,
+	}
+}
+
+// fmtReview describes the review, i.e. "approved, 3 review comments".
+func fmtReview(review *Review) string {
+	if review == nil {
+		return ""
+	}
+
+	state := strings.ReplaceAll(review.State, "_", " ")
+	if review.Comments == 0 {
+		return state
+	}
+
+	return fmt.Sprintf("%s, %s", state, plural(review.Comments, "review comment", "review comments"))
+}
@@ -0,0 +1,48 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReview(t *testing.T) {
+	t.Parallel()
+
+	reviewsWithStates := func(states ...string) []*github.PullRequestReview {
+		reviews := make([]*github.PullRequestReview, 0, len(states))
+		for _, state := range states {
+			reviews = append(reviews, &github.PullRequestReview{State: github.String(state)})
+		}
+		return reviews
+	}
+
+	assert.Equal(t, &format.Review{State: format.ReviewCommented, Comments: 2},
+		format.NewReview(reviewsWithStates("COMMENTED"), 2))
+	assert.Equal(t, &format.Review{State: format.ReviewApproved},
+		format.NewReview(reviewsWithStates("CHANGES_REQUESTED", "COMMENTED", "APPROVED", "COMMENTED"), 0))
+	assert.Equal(t, &format.Review{State: format.ReviewChangesRequested},
+		format.NewReview(reviewsWithStates("APPROVED", "CHANGES_REQUESTED"), 0))
+}
+
+func TestFormatMarkdownItemWithReview(t *testing.T) {
+	t.Parallel()
+
+	item := &format.Item{
+		Type:       format.ItemTypePullRequest,
+		Number:     7,
+		Repository: "octo/widgets",
+		Status:     "merged",
+		Title:      "Oil the sprockets",
+		HTMLURL:    "https://github.com/octo/widgets/pull/7",
+		Review: &format.Review{
+			State:    format.ReviewChangesRequested,
+			Comments: 1,
+		},
+	}
+	assert.Equal(t,
+		"- PR [octo/widgets#7](https://github.com/octo/widgets/pull/7) **merged** - Oil the sprockets _changes requested, 1 review comment_",
+		format.FormatMarkdownItem(item, true))
+}
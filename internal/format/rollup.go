@@ -0,0 +1,172 @@
+package format
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/samber/lo"
+)
+
+// RollupVersion is the version of the rollup schema, which changes like
+// ReportVersion. The reports within the rollup follow ReportVersion.
+const RollupVersion = "1"
+
+// RollupSchema is the JSON Schema describing the JSON encoding of Rollup.
+//
+//go:embed rollup.schema.json
+var RollupSchema []byte
+
+// Totals count what a person, or the whole team, got done within the window.
+type Totals struct {
+	// Pull requests that the person authored and were merged within the window.
+	PullRequestsMerged int `json:"pull_requests_merged"`
+	// Pull requests by other authors that the person reviewed within the window.
+	ReviewsGiven int `json:"reviews_given"`
+	// Issues that the person authored and were closed within the window.
+	IssuesClosed int `json:"issues_closed"`
+}
+
+// Add adds the other totals to these.
+func (t *Totals) Add(other Totals) {
+	t.PullRequestsMerged += other.PullRequestsMerged
+	t.ReviewsGiven += other.ReviewsGiven
+	t.IssuesClosed += other.IssuesClosed
+}
+
+// Person is one person's report within a rollup.
+type Person struct {
+	User   string  `json:"user"`
+	Totals Totals  `json:"totals"`
+	Report *Report `json:"report"`
+}
+
+// Rollup combines the reports of several people over the same window, with the
+// totals for the whole team.
+type Rollup struct {
+	Version string `json:"version"`
+	// GitHub team that the users are on, formatted as org/team-slug, if the users
+	// were given as a team.
+	Team   string   `json:"team,omitempty"`
+	Users  []string `json:"users"`
+	Window Window   `json:"window"`
+	Scope  Scope    `json:"scope"`
+	// Sentence that opens the rollup, i.e. "For the period of 2026-10-05 to
+	// 2026-10-18 (2 weeks), the team of 2 merged 3 pull requests, ..."
+	Summary string    `json:"summary"`
+	Totals  Totals    `json:"totals"`
+	People  []*Person `json:"people"`
+}
+
+// NewRollup combines the reports of the people, which all cover the same window
+// and scope, in the order that they're given.
+func NewRollup(people []*Person) *Rollup {
+	r := &Rollup{
+		Version: RollupVersion,
+		Users:   lo.Map(people, func(p *Person, _ int) string { return p.User }),
+		People:  people,
+	}
+	if len(people) > 0 {
+		r.Window = people[0].Report.Window
+		r.Scope = people[0].Report.Scope
+	}
+	for _, p := range people {
+		r.Totals.Add(p.Totals)
+	}
+
+	r.Summary = fmt.Sprintf("For the period of %s (%s), the team of %d merged %s, gave %s, and closed %s.",
+		fmtDateRange(r.Window), DurationAsLength(r.Window.End.Sub(r.Window.Start)), len(people),
+		plural(r.Totals.PullRequestsMerged, "pull request", "pull requests"),
+		plural(r.Totals.ReviewsGiven, "review", "reviews"),
+		plural(r.Totals.IssuesClosed, "issue", "issues"))
+
+	return r
+}
+
+// title is the heading of the rollup, i.e. "biweekly team report for octo/eng".
+func (r *Rollup) title() string {
+	if len(r.Team) == 0 {
+		return fmt.Sprintf("%s team report", r.Window.Period)
+	}
+
+	return fmt.Sprintf("%s team report for %s", r.Window.Period, r.Team)
+}
+
+// RenderRollupMarkdown renders the rollup as Markdown, with a table of the totals
+// followed by a section for each person.
+func RenderRollupMarkdown(r *Rollup) string {
+	var out bytes.Buffer
+	out.WriteString(fmt.Sprintf("# %s: %s\n\n", markdownEscaper.Replace(r.title()), r.Window.Start.Format("2006-01-02")))
+	out.WriteString(r.Summary)
+	out.WriteString("\n\n")
+
+	out.WriteString("| Person | PRs merged | Reviews given | Issues closed |\n")
+	out.WriteString("| --- | --- | --- | --- |\n")
+	row := func(name string, t Totals) {
+		out.WriteString(fmt.Sprintf("| %s | %d | %d | %d |\n", name, t.PullRequestsMerged, t.ReviewsGiven, t.IssuesClosed))
+	}
+	for _, p := range r.People {
+		row(markdownEscaper.Replace(p.User), p.Totals)
+	}
+	row("**Team**", r.Totals)
+	out.WriteRune('\n')
+
+	for _, p := range r.People {
+		out.WriteString(fmt.Sprintf("## %s\n\n", markdownEscaper.Replace(p.User)))
+		if len(p.Report.Summary) > 0 {
+			out.WriteString(p.Report.Summary)
+			out.WriteString("\n\n")
+		}
+		for _, section := range p.Report.Sections {
+			out.WriteString(formatMarkdownSection(section, !p.Report.SingleRepository(), "### "))
+		}
+	}
+
+	return out.String()
+}
+
+// RenderRollupText renders the rollup for display in a terminal.
+func RenderRollupText(r *Rollup) string {
+	var out bytes.Buffer
+	out.WriteString(fmt.Sprintf("# %s: %s\n\n", r.title(), r.Window.Start.Format("2006-01-02")))
+	out.WriteString(r.Summary)
+	out.WriteString("\n\n")
+
+	width := lo.Max(lo.Map(append(r.Users, "Team"), func(user string, _ int) int { return len(user) }))
+	line := func(name string, t Totals) {
+		out.WriteString(fmt.Sprintf("%-*s  %s, %s, %s\n", width, name,
+			plural(t.PullRequestsMerged, "PR merged", "PRs merged"),
+			plural(t.ReviewsGiven, "review given", "reviews given"),
+			plural(t.IssuesClosed, "issue closed", "issues closed")))
+	}
+	for _, p := range r.People {
+		line(p.User, p.Totals)
+	}
+	line("Team", r.Totals)
+	out.WriteRune('\n')
+
+	for _, p := range r.People {
+		out.WriteString(fmt.Sprintf("## %s\n\n", p.User))
+		if len(p.Report.Summary) > 0 {
+			out.WriteString(p.Report.Summary)
+			out.WriteString("\n\n")
+		}
+		for _, section := range p.Report.Sections {
+			out.WriteString(formatSection(section, !p.Report.SingleRepository(), "### "))
+		}
+	}
+
+	return out.String()
+}
+
+// RenderRollupJSON encodes the rollup as indented JSON matching RollupSchema, where
+// each person's report matches ReportSchema.
+func RenderRollupJSON(r *Rollup) (string, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
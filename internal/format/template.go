@@ -0,0 +1,126 @@
+package format
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+const templateExt = ".tmpl"
+
+// TemplateNames returns the names of the built-in templates.
+func TemplateNames() []string {
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return nil
+	}
+
+	names := lo.Map(entries, func(entry os.DirEntry, _ int) string {
+		return strings.TrimSuffix(entry.Name(), templateExt)
+	})
+	sort.Strings(names)
+	return names
+}
+
+// TemplateFuncs are the helpers available to report templates, on top of the
+// text/template builtins.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// Formats the time as a date, i.e. "2026-10-05".
+		"date": func(t time.Time) string {
+			return t.Format("2006-01-02")
+		},
+		// Rounds a duration or a number of seconds up, i.e. "<=3 days".
+		"duration": func(v any) (string, error) {
+			switch d := v.(type) {
+			case time.Duration:
+				return roughDuration(d), nil
+			case int64:
+				return roughDuration(time.Duration(d) * time.Second), nil
+			case int:
+				return roughDuration(time.Duration(d) * time.Second), nil
+			}
+			return "", fmt.Errorf("duration of %T, expected a time.Duration or seconds", v)
+		},
+		// Formats the reaction counts as emoji, i.e. "2 👍 1 🚀".
+		"reactions": joinReactions,
+		// Formats the window as the first and last day, i.e. "2026-10-05 to 2026-10-18".
+		"dateRange": fmtDateRange,
+		// Formats a Markdown link.
+		"link": func(text string, url string) string {
+			return fmt.Sprintf("[%s](%s)", text, url)
+		},
+		// Formats the count with the singular or plural noun, i.e. "1 comment".
+		"plural": plural,
+		// Escapes Markdown in text, i.e. item titles.
+		"escape": markdownEscaper.Replace,
+		// Formats the GitHub reference for the item, i.e. "owner/repo#12" or "#12".
+		"ref": Ref,
+		// Groups the items by repository when the repository is shown.
+		"sortByRepository": sortByRepository,
+		// Describes the review, i.e. "approved, 3 review comments".
+		"review": fmtReview,
+		// Names the kind of item, i.e. "PR".
+		"kind": Kind,
+		// Formats the lines added and deleted, i.e. "+10 -2".
+		"diffstat": fmtDiffstat,
+		// Describes the release that shipped the item, i.e. "Shipped in v1.4.0".
+		"shipped": fmtShipped,
+		"join":    strings.Join,
+		"lower":   strings.ToLower,
+	}
+}
+
+// fmtDateRange formats the window as the first and last day that it covers.
+func fmtDateRange(w Window) string {
+	return fmt.Sprintf("%s to %s", w.Start.Format("2006-01-02"), w.End.Add(-time.Nanosecond).Format("2006-01-02"))
+}
+
+// LoadTemplate parses the built-in template with the name, or otherwise the
+// template file at the path.
+func LoadTemplate(nameOrPath string) (*template.Template, error) {
+	t := template.New(filepath.Base(nameOrPath)).Funcs(TemplateFuncs())
+
+	if lo.Contains(TemplateNames(), nameOrPath) {
+		data, err := builtinTemplates.ReadFile("templates/" + nameOrPath + templateExt)
+		if err != nil {
+			return nil, err
+		}
+		return t.Parse(string(data))
+	}
+
+	data, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		return nil, fmt.Errorf("read template (or use one of the built-in templates: %s): %w",
+			strings.Join(TemplateNames(), ", "), err)
+	}
+
+	t, err = t.Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	return t, nil
+}
+
+// RenderTemplate renders the report with the template, which is given the report
+// as its data.
+func RenderTemplate(r *Report, t *template.Template) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, r); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+
+	return buf.String(), nil
+}
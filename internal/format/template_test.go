@@ -0,0 +1,103 @@
+package format_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateNames(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"narrative", "table", "terse"}, format.TemplateNames())
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string][]string{
+		"terse": {
+			"# octocat: 2026-10-05 to 2026-10-18\n",
+			"\n**Completed this cycle**\n- [octo/widgets#12](https://github.com/octo/widgets/pull/12) Add sprockets\n",
+		},
+		"narrative": {
+			"For the period of 2026-10-05 to 2026-10-18, here's what octocat worked on.\n",
+			"## Completed this cycle (1)\n",
+			"- Add sprockets (pull request [octo/widgets#12](https://github.com/octo/widgets/pull/12)), merged, drawing 2 👍 1 🚀.\n",
+		},
+		"table": {
+			"| PR | [octo/widgets#12](https://github.com/octo/widgets/pull/12) | merged | <=3 days | Add sprockets | 2 👍 1 🚀 |\n",
+			"| Issue | [octo/widgets#13](https://github.com/octo/widgets/issues/13) | active | <=",
+		},
+	} {
+		tmpl, err := format.LoadTemplate(name)
+		require.NoError(t, err, name)
+
+		out, err := format.RenderTemplate(testReport(), tmpl)
+		require.NoError(t, err, name)
+		assert.NotContains(t, out, "<no value>", name)
+		for _, s := range want {
+			assert.Contains(t, out, s, name)
+		}
+	}
+}
+
+func TestBuiltinTemplatesHighlight(t *testing.T) {
+	t.Parallel()
+
+	r := testReport()
+	r.Sections[0].Items[0].Highlight = true
+	r.Sections[0].Items[0].Note = "Unblocked the launch"
+	for name, want := range map[string]string{
+		"terse":     "- ⭐ [octo/widgets#12](https://github.com/octo/widgets/pull/12) Add sprockets — Unblocked the launch\n",
+		"narrative": "- ⭐ Add sprockets (pull request [octo/widgets#12](https://github.com/octo/widgets/pull/12)), merged, drawing 2 👍 1 🚀 — Unblocked the launch.\n",
+		"table":     "| ⭐ Add sprockets — Unblocked the launch | 2 👍 1 🚀 |\n",
+	} {
+		tmpl, err := format.LoadTemplate(name)
+		require.NoError(t, err, name)
+
+		out, err := format.RenderTemplate(r, tmpl)
+		require.NoError(t, err, name)
+		assert.Contains(t, out, want, name)
+	}
+}
+
+func TestTemplateFromFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "custom.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(
+		`{{ range .Sections }}{{ .Title }}: {{ plural (len .Items) "item" "items" }}{{ range .Items }} {{ ref . false }} {{ duration .DurationSeconds }}{{ end }}
+{{ end }}`), 0o644))
+
+	tmpl, err := format.LoadTemplate(path)
+	require.NoError(t, err)
+
+	out, err := format.RenderTemplate(testReport(), tmpl)
+	require.NoError(t, err)
+	// The open issue's duration depends on the time that the test runs.
+	assert.True(t, strings.HasPrefix(out, "Completed this cycle: 1 item #12 <=3 days\nRemaining: 1 item #13 <="), out)
+}
+
+func TestTemplateErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := format.LoadTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	assert.ErrorContains(t, err, "narrative, table, terse")
+
+	path := filepath.Join(t.TempDir(), "broken.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`{{ range .Sections }}`), 0o644))
+	_, err = format.LoadTemplate(path)
+	assert.ErrorContains(t, err, "parse template")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{{ duration .User }}`), 0o644))
+	tmpl, err := format.LoadTemplate(path)
+	require.NoError(t, err)
+	_, err = format.RenderTemplate(testReport(), tmpl)
+	assert.ErrorContains(t, err, "expected a time.Duration or seconds")
+}
@@ -0,0 +1,192 @@
+// Package gitlog reads commits from local git repositories.
+package gitlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/util"
+	"github.com/samber/lo"
+)
+
+// Commit is a single commit, with its diffstat.
+type Commit struct {
+	SHA         string
+	AuthorEmail string
+	AuthoredAt  time.Time
+	Subject     string
+	Body        string
+	Additions   int
+	Deletions   int
+	// Number of the pull request that the commit came from, or 0 if the commit
+	// doesn't mention one.
+	PullRequest int
+}
+
+const (
+	// Separates the commits in the log, which starts each commit.
+	recordSep = "\x1e"
+	// Separates the fields of the commit.
+	fieldSep = "\x1f"
+	// Fields of the commit, which are followed by its --numstat lines.
+	logFormat = recordSep + "%H" + fieldSep + "%ae" + fieldSep + "%aI" + fieldSep + "%s" + fieldSep + "%b" + fieldSep
+)
+
+// git runs the git command in the repository and returns its output.
+func git(ctx context.Context, repo string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repo}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s in %s: %w: %s", args[0], repo, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// Emails returns the user.email values configured for the repository, including
+// the global and system configuration.
+func Emails(ctx context.Context, repo string) ([]string, error) {
+	out, err := git(ctx, repo, "config", "--get-all", "user.email")
+	// git exits with 1 when the key isn't set at all.
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, err
+	}
+
+	return lo.Compact(strings.Fields(out)), nil
+}
+
+// githubRemote matches the owner and name of a repository in HTTPS and SSH remote
+// URLs, i.e. "https://github.com/octo/widgets.git" or
+// "git@github.example.com:octo/widgets.git".
+var githubRemote = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?([^/:]+)[/:]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// Remote returns the host and owner/name of the repository's origin remote. Both
+// are empty if there is no origin or it isn't recognized.
+func Remote(ctx context.Context, repo string) (string, string) {
+	out, err := git(ctx, repo, "remote", "get-url", "origin")
+	if err != nil {
+		return "", ""
+	}
+
+	m := githubRemote.FindStringSubmatch(strings.TrimSpace(out))
+	if m == nil {
+		return "", ""
+	}
+
+	return m[1], m[2] + "/" + m[3]
+}
+
+// defaultBranch returns the branch that pull requests are merged into: origin's
+// HEAD if it's known, i.e. from cloning, or else whatever is checked out.
+func defaultBranch(ctx context.Context, repo string) string {
+	if _, err := git(ctx, repo, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/HEAD"); err == nil {
+		return "refs/remotes/origin/HEAD"
+	}
+
+	return "HEAD"
+}
+
+// Log returns the commits on the repository's default branch authored by any of
+// the emails within [start, end), newest first. Other branches are left out, since
+// their commits either haven't landed or landed as a squashed commit. Merge
+// commits are left out too, since the commits that they merge are already
+// included.
+func Log(ctx context.Context, repo string, emails []string, start time.Time, end time.Time) ([]*Commit, error) {
+	// --since filters by the committer date, which is never before the author
+	// date, so it only narrows the log down without losing any commits.
+	out, err := git(ctx, repo, "log", defaultBranch(ctx, repo), "--no-merges", "--numstat", "--format="+logFormat,
+		"--since="+start.Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*Commit
+	for _, record := range strings.Split(out, recordSep) {
+		if len(strings.TrimSpace(record)) == 0 {
+			continue
+		}
+
+		c, err := parseCommit(record)
+		if err != nil {
+			return nil, fmt.Errorf("git log in %s: %w", repo, err)
+		}
+
+		if !util.InTimeRange(c.AuthoredAt, start, end) {
+			continue
+		}
+		if !lo.ContainsBy(emails, func(email string) bool { return strings.EqualFold(email, c.AuthorEmail) }) {
+			continue
+		}
+
+		commits = append(commits, c)
+	}
+
+	return commits, nil
+}
+
+func parseCommit(record string) (*Commit, error) {
+	fields := strings.SplitN(record, fieldSep, 6)
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("unexpected commit `%s`", record)
+	}
+
+	authoredAt, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: %w", fields[0], err)
+	}
+
+	c := &Commit{
+		SHA:         fields[0],
+		AuthorEmail: fields[1],
+		AuthoredAt:  authoredAt,
+		Subject:     fields[3],
+		Body:        strings.TrimSpace(fields[4]),
+	}
+	c.PullRequest = pullRequest(c.Subject, c.Body)
+
+	// Each line is "<additions>\t<deletions>\t<path>", where binary files have
+	// "-" instead of counts.
+	for _, line := range strings.Split(fields[5], "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(parts[0])
+		deleted, _ := strconv.Atoi(parts[1])
+		c.Additions += added
+		c.Deletions += deleted
+	}
+
+	return c, nil
+}
+
+var (
+	// GitHub's squash and merge suffix, i.e. "Add sprockets (#12)".
+	subjectRef = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+	// Trailers pointing at the pull request, i.e. "Pull-Request: #12" or
+	// "PR: https://github.com/octo/widgets/pull/12".
+	trailerRef = regexp.MustCompile(`(?im)^(?:pull-request|pr):\s*(?:#|\S*/pull/)(\d+)\s*$`)
+)
+
+// pullRequest returns the number of the pull request that the commit mentions in
+// its trailers or subject, or 0 if it doesn't mention one.
+func pullRequest(subject string, body string) int {
+	for _, m := range [][]string{trailerRef.FindStringSubmatch(body), subjectRef.FindStringSubmatch(subject)} {
+		if m != nil {
+			n, _ := strconv.Atoi(m[1])
+			return n
+		}
+	}
+
+	return 0
+}
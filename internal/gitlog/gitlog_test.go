@@ -0,0 +1,117 @@
+package gitlog_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/gitlog"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitRepo creates a repository with the origin remote, where commit adds a commit
+// authored by the email at the time. Callers ignore the user's git configuration
+// with GIT_CONFIG_GLOBAL and GIT_CONFIG_NOSYSTEM.
+func gitRepo(t *testing.T, origin string) (string, func(email string, at time.Time, file string, lines int, message string)) {
+	t.Helper()
+
+	dir := t.TempDir()
+	git := func(env []string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	git(nil, "init", "--quiet")
+	git(nil, "config", "user.email", "octocat@example.com")
+	git(nil, "config", "user.name", "Octocat")
+	if len(origin) > 0 {
+		git(nil, "remote", "add", "origin", origin)
+	}
+
+	return dir, func(email string, at time.Time, file string, lines int, message string) {
+		content := ""
+		for i := 0; i < lines; i++ {
+			content += "line\n"
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+		git(nil, "add", file)
+		date := at.Format(time.RFC3339)
+		git([]string{"GIT_AUTHOR_DATE=" + date, "GIT_COMMITTER_DATE=" + date},
+			"-c", "user.email="+email, "commit", "--quiet", "-m", message)
+	}
+}
+
+func TestLog(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	start := time.Date(2026, time.October, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 14)
+	dir, commit := gitRepo(t, "git@github.com:octo/widgets.git")
+	commit("octocat@example.com", start.Add(-time.Hour), "old.txt", 1, "Before the window")
+	commit("Octocat@Example.com", start.Add(time.Hour), "sprockets.txt", 3, "Add sprockets (#12)")
+	commit("hubot@example.com", start.Add(2*time.Hour), "hubot.txt", 1, "Someone else")
+	commit("octocat@example.com", start.Add(3*time.Hour), "docs.txt", 2, "Fix the docs")
+	commit("octocat@example.com", start.Add(4*time.Hour), "gears.txt", 1,
+		"Speed up gears\n\nPR: https://github.com/octo/widgets/pull/40")
+	commit("octocat@example.com", end.Add(time.Hour), "new.txt", 1, "After the window")
+
+	// The commits of a pull request that hasn't landed, or was squashed, are only
+	// on its branch.
+	git := func(args ...string) {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("update-ref", "refs/remotes/origin/main", "HEAD")
+	git("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main")
+	git("checkout", "--quiet", "-b", "sprockets")
+	commit("octocat@example.com", start.Add(5*time.Hour), "wip.txt", 1, "WIP sprockets")
+
+	ctx := context.Background()
+	emails, err := gitlog.Emails(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"octocat@example.com"}, emails)
+
+	host, repo := gitlog.Remote(ctx, dir)
+	assert.Equal(t, "github.com", host)
+	assert.Equal(t, "octo/widgets", repo)
+
+	commits, err := gitlog.Log(ctx, dir, emails, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Speed up gears", "Fix the docs", "Add sprockets (#12)"},
+		lo.Map(commits, func(c *gitlog.Commit, _ int) string { return c.Subject }))
+	assert.Equal(t, []int{40, 0, 12},
+		lo.Map(commits, func(c *gitlog.Commit, _ int) int { return c.PullRequest }))
+	assert.Equal(t, 3, commits[2].Additions)
+	assert.Equal(t, 0, commits[2].Deletions)
+	assert.Len(t, commits[2].SHA, 40)
+	assert.True(t, commits[2].AuthoredAt.Equal(start.Add(time.Hour)))
+
+	_, err = gitlog.Log(ctx, t.TempDir(), emails, start, end)
+	assert.ErrorContains(t, err, "git log")
+}
+
+func TestRemote(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	for origin, want := range map[string][2]string{
+		"https://github.com/octo/widgets.git":        {"github.com", "octo/widgets"},
+		"https://github.com/octo/widgets":            {"github.com", "octo/widgets"},
+		"git@github.example.com:octo/widgets.git":    {"github.example.com", "octo/widgets"},
+		"ssh://git@github.example.com/octo/widgets/": {"github.example.com", "octo/widgets"},
+		"https://github.com/octo/widgets/extra/path": {"", ""},
+		"": {"", ""},
+	} {
+		dir, _ := gitRepo(t, origin)
+		host, repo := gitlog.Remote(context.Background(), dir)
+		assert.Equal(t, want, [2]string{host, repo}, origin)
+	}
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal"
+)
+
+const dateLayout = "2006-01-02"
+
+// FileName names the report covering [start, end) by its first and last day, i.e.
+// "2026-10-05_2026-10-18-weekly.md". The period is left out if it's empty.
+func FileName(start time.Time, end time.Time, period string, ext string) string {
+	name := fmt.Sprintf("%s_%s",
+		start.Local().Format(dateLayout),
+		end.Add(-time.Second).Local().Format(dateLayout))
+	if len(period) > 0 {
+		name = name + "-" + period
+	}
+
+	return name + ext
+}
+
+// WriteFile atomically writes the data to the path, so that a crash never leaves
+// a partially written file behind. Unless force is true, an existing file at the
+// path is never overwritten.
+func WriteFile(path string, data []byte, force bool) error {
+	dir, base := filepath.Split(path)
+	if len(dir) == 0 {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file for %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", tmp.Name(), err)
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync %s: %w", tmp.Name(), err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", tmp.Name(), err)
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("chmod %s: %w", tmp.Name(), err)
+	}
+
+	if force {
+		if err = os.Rename(tmp.Name(), path); err != nil {
+			return fmt.Errorf("rename %s to %s: %w", tmp.Name(), path, err)
+		}
+		return nil
+	}
+
+	// Linking fails if the path already exists, so nothing can be overwritten
+	// between checking for the file and writing it.
+	err = os.Link(tmp.Name(), path)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+	}
+	if err != nil {
+		internal.Log().Debug().Err(err).Msg("hard links unsupported, falling back to rename")
+		if _, statErr := os.Stat(path); statErr == nil {
+			return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+		}
+		if err = os.Rename(tmp.Name(), path); err != nil {
+			return fmt.Errorf("rename %s to %s: %w", tmp.Name(), path, err)
+		}
+	}
+
+	return nil
+}
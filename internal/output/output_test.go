@@ -0,0 +1,50 @@
+package output_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileName(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.October, 5, 0, 0, 0, 0, time.Local)
+
+	assert.Equal(t, "2026-10-05_2026-10-18-biweekly.md",
+		output.FileName(start, start.AddDate(0, 0, 14), "biweekly", ".md"))
+	assert.Equal(t, "2026-10-05_2026-10-05.json",
+		output.FileName(start, start.Add(5*time.Hour), "", ".json"))
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.md")
+
+	require.NoError(t, output.WriteFile(path, []byte("first"), false))
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(got))
+
+	assert.Error(t, output.WriteFile(path, []byte("second"), false))
+	got, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(got))
+
+	require.NoError(t, output.WriteFile(path, []byte("third"), true))
+	got, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "third", string(got))
+
+	// No temporary files should be left behind.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
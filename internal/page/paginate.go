@@ -37,7 +37,9 @@ func Paginate(detail string, perPage int, fn func(listOptions github.ListOptions
 			return err
 		}
 
-		if details.StopEarly || resp.LastPage == pageNum {
+		// Responses without any more pages, such as a single page of results, don't
+		// have a next page.
+		if details.StopEarly || resp.LastPage == pageNum || resp.NextPage == 0 {
 			return nil
 		}
 
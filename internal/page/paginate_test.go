@@ -59,6 +59,15 @@ func TestUntilLastPageWithRateLimitOnEveryCall(t *testing.T) {
 	assert.Equal(t, totalPages*2, testCount)
 }
 
+func TestStopWithoutNextPage(t *testing.T) {
+	testCount := 0
+	assert.NoError(t, page.Paginate("test", 10, func(listOptions github.ListOptions) (page.Details, *github.Response, error) {
+		testCount++
+		return page.Details{}, &github.Response{}, nil
+	}))
+	assert.Equal(t, 1, testCount)
+}
+
 func TestPassAlongError(t *testing.T) {
 	assert.Error(t, page.Paginate("test", 10, func(listOptions github.ListOptions) (page.Details, *github.Response, error) {
 		return page.Details{}, &github.Response{}, fmt.Errorf("some error")
@@ -0,0 +1,226 @@
+package pulls
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/ratelimit"
+	"github.com/google/go-github/v53/github"
+)
+
+// MaxBatch is the most pull requests that are fetched in a single query.
+const MaxBatch = 100
+
+// Ref identifies a pull request.
+type Ref struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+func (r Ref) String() string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// Details are the parts of a pull request that issue search results don't
+// include.
+type Details struct {
+	Merged   bool
+	MergedAt *time.Time
+	// One of APPROVED, CHANGES_REQUESTED, or REVIEW_REQUIRED, or empty if the
+	// repository doesn't require reviews.
+	ReviewDecision string
+	Additions      int
+	Deletions      int
+	Labels         []string
+	// SHA of the commit that the pull request was merged as, if it was merged.
+	MergeCommitSHA string
+}
+
+const fields = `fragment fields on PullRequest {
+  merged
+  mergedAt
+  reviewDecision
+  additions
+  deletions
+  mergeCommit {
+    oid
+  }
+  labels(first: 100) {
+    nodes {
+      name
+    }
+  }
+}`
+
+type pullRequestNode struct {
+	Merged         bool       `json:"merged"`
+	MergedAt       *time.Time `json:"mergedAt"`
+	ReviewDecision string     `json:"reviewDecision"`
+	Additions      int        `json:"additions"`
+	Deletions      int        `json:"deletions"`
+	MergeCommit    *struct {
+		OID string `json:"oid"`
+	} `json:"mergeCommit"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data map[string]*struct {
+		PullRequest *pullRequestNode `json:"pullRequest"`
+	} `json:"data"`
+	Errors []struct {
+		// Type of the error, i.e. NOT_FOUND or RATE_LIMITED.
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Fetch retrieves the details of the pull requests through the GraphQL API, up to
+// MaxBatch pull requests per query. Pull requests that couldn't be found are left
+// out of the result, so the caller can fall back to the REST API for them.
+func Fetch(ctx context.Context, client *github.Client, refs []Ref) (map[Ref]*Details, error) {
+	endpoint := Endpoint(client.BaseURL)
+	details := make(map[Ref]*Details, len(refs))
+	for start := 0; start < len(refs); start += MaxBatch {
+		end := start + MaxBatch
+		if end > len(refs) {
+			end = len(refs)
+		}
+
+		batch := refs[start:end]
+		internal.Log().Debug().Int("pull requests", len(batch)).Msg("fetch pull request details")
+		res, err := query(ctx, client, endpoint, batch)
+		if err != nil {
+			return details, err
+		}
+
+		for i, ref := range batch {
+			repo := res.Data[fmt.Sprintf("pr%d", i)]
+			if repo == nil || repo.PullRequest == nil {
+				internal.Log().Debug().Str("pull request", ref.String()).Msg("pull request details not found")
+				continue
+			}
+
+			pr := repo.PullRequest
+			labels := make([]string, 0, len(pr.Labels.Nodes))
+			for _, label := range pr.Labels.Nodes {
+				labels = append(labels, label.Name)
+			}
+
+			details[ref] = &Details{
+				Merged:         pr.Merged,
+				MergedAt:       pr.MergedAt,
+				ReviewDecision: pr.ReviewDecision,
+				Additions:      pr.Additions,
+				Deletions:      pr.Deletions,
+				Labels:         labels,
+			}
+			if pr.MergeCommit != nil {
+				details[ref].MergeCommitSHA = pr.MergeCommit.OID
+			}
+		}
+	}
+
+	return details, nil
+}
+
+// Endpoint returns the GraphQL endpoint for the REST API base URL. On github.com
+// this is https://api.github.com/graphql, and on GitHub Enterprise Server it's
+// https://<host>/api/graphql.
+func Endpoint(baseURL *url.URL) string {
+	u := *baseURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "/v3/") + "/graphql"
+	} else {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/graphql"
+	}
+
+	return u.String()
+}
+
+// query builds a single query with an aliased field for each pull request, so that
+// the whole batch is fetched in one round trip.
+func query(ctx context.Context, client *github.Client, endpoint string, batch []Ref) (*graphQLResponse, error) {
+	var (
+		params  []string
+		aliases bytes.Buffer
+	)
+	variables := make(map[string]any, 3*len(batch))
+	for i, ref := range batch {
+		params = append(params, fmt.Sprintf("$o%d: String!, $r%d: String!, $n%d: Int!", i, i, i))
+		aliases.WriteString(fmt.Sprintf("  pr%d: repository(owner: $o%d, name: $r%d) { pullRequest(number: $n%d) { ...fields } }\n", i, i, i, i))
+		variables[fmt.Sprintf("o%d", i)] = ref.Owner
+		variables[fmt.Sprintf("r%d", i)] = ref.Repo
+		variables[fmt.Sprintf("n%d", i)] = ref.Number
+	}
+
+	body := graphQLRequest{
+		Query:     fmt.Sprintf("query(%s) {\n%s}\n%s", strings.Join(params, ", "), aliases.String(), fields),
+		Variables: variables,
+	}
+
+	for {
+		req, err := client.NewRequest("POST", endpoint, body)
+		if err != nil {
+			return nil, err
+		}
+
+		var res graphQLResponse
+		resp, err := client.Do(ctx, req, &res)
+		if err == nil {
+			err = rateLimitError(resp, &res)
+		}
+		if ratelimit.WaitIfRateLimited(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query pull request details: %w", err)
+		}
+
+		// Missing pull requests are reported as errors alongside the rest of the
+		// data, so only fail if there's no data at all.
+		if len(res.Data) == 0 && len(res.Errors) > 0 {
+			return nil, fmt.Errorf("query pull request details: %s", res.Errors[0].Message)
+		}
+		for _, e := range res.Errors {
+			internal.Log().Debug().Str("error", e.Message).Msg("partial error querying pull request details")
+		}
+
+		return &res, nil
+	}
+}
+
+// rateLimitError returns the error that the REST API would have failed with if the
+// query was rate limited. GraphQL reports rate limits as errors in a 200 OK
+// response, so the client doesn't notice them on its own.
+func rateLimitError(resp *github.Response, res *graphQLResponse) error {
+	for _, e := range res.Errors {
+		if e.Type != "RATE_LIMITED" {
+			continue
+		}
+
+		// Without the rate limit headers there's no telling when the limit resets,
+		// so wait as long as for a secondary rate limit.
+		if resp.Rate.Reset.IsZero() {
+			return &github.AbuseRateLimitError{Response: resp.Response, Message: e.Message}
+		}
+		return &github.RateLimitError{Rate: resp.Rate, Response: resp.Response, Message: e.Message}
+	}
+
+	return nil
+}
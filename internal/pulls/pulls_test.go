@@ -0,0 +1,149 @@
+package pulls_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/chrisyxlee/snippets/internal/pulls"
+	"github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer answers GraphQL queries for pull requests, treating even numbers as
+// merged and numbers over 1000 as missing. The first rateLimited queries are
+// rejected the way GraphQL rejects them, with a 200 OK.
+func newServer(t *testing.T, queries *int, rateLimited int) *github.Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		*queries++
+		w.Header().Set("Content-Type", "application/json")
+		if *queries <= rateLimited {
+			// The limit already reset, so that the query is retried right away.
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1")
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"data": nil,
+				"errors": []map[string]any{
+					{"type": "RATE_LIMITED", "message": "API rate limit exceeded for user ID 1."},
+				},
+			}))
+			return
+		}
+
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		data := make(map[string]any)
+		for i := 0; ; i++ {
+			n, ok := req.Variables[fmt.Sprintf("n%d", i)]
+			if !ok {
+				break
+			}
+
+			number := int(n.(float64))
+			alias := fmt.Sprintf("pr%d", i)
+			if number > 1000 {
+				data[alias] = nil
+				continue
+			}
+
+			pr := map[string]any{
+				"merged":         number%2 == 0,
+				"mergedAt":       nil,
+				"reviewDecision": "APPROVED",
+				"additions":      number,
+				"deletions":      1,
+				"labels": map[string]any{
+					"nodes": []map[string]any{{"name": req.Variables[fmt.Sprintf("r%d", i)]}},
+				},
+			}
+			if number%2 == 0 {
+				pr["mergedAt"] = "2026-10-06T12:00:00Z"
+				pr["mergeCommit"] = map[string]any{"oid": fmt.Sprintf("%040d", number)}
+			}
+			data[alias] = map[string]any{"pullRequest": pr}
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+	return client
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	var queries int
+	client := newServer(t, &queries, 0)
+
+	var refs []pulls.Ref
+	for i := 1; i <= 150; i++ {
+		refs = append(refs, pulls.Ref{Owner: "octo", Repo: "widgets", Number: i})
+	}
+	missing := pulls.Ref{Owner: "octo", Repo: "gone", Number: 1001}
+	refs = append(refs, missing)
+
+	details, err := pulls.Fetch(context.Background(), client, refs)
+	require.NoError(t, err)
+	assert.Equal(t, 2, queries)
+	assert.Len(t, details, 150)
+	assert.NotContains(t, details, missing)
+
+	merged := details[pulls.Ref{Owner: "octo", Repo: "widgets", Number: 42}]
+	require.NotNil(t, merged)
+	assert.True(t, merged.Merged)
+	require.NotNil(t, merged.MergedAt)
+	assert.Equal(t, 2026, merged.MergedAt.Year())
+	assert.Equal(t, "APPROVED", merged.ReviewDecision)
+	assert.Equal(t, 42, merged.Additions)
+	assert.Equal(t, []string{"widgets"}, merged.Labels)
+	assert.Equal(t, "0000000000000000000000000000000000000042", merged.MergeCommitSHA)
+
+	open := details[pulls.Ref{Owner: "octo", Repo: "widgets", Number: 7}]
+	require.NotNil(t, open)
+	assert.False(t, open.Merged)
+	assert.Nil(t, open.MergedAt)
+	assert.Empty(t, open.MergeCommitSHA)
+}
+
+func TestFetchRateLimited(t *testing.T) {
+	t.Parallel()
+
+	var queries int
+	client := newServer(t, &queries, 2)
+
+	ref := pulls.Ref{Owner: "octo", Repo: "widgets", Number: 42}
+	details, err := pulls.Fetch(context.Background(), client, []pulls.Ref{ref})
+	require.NoError(t, err)
+	assert.Equal(t, 3, queries)
+	require.Contains(t, details, ref)
+	assert.True(t, details[ref].Merged)
+}
+
+func TestEndpoint(t *testing.T) {
+	t.Parallel()
+
+	dotcom, err := url.Parse("https://api.github.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.github.com/graphql", pulls.Endpoint(dotcom))
+
+	enterprise, err := url.Parse("https://github.example.com/api/v3/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/api/graphql", pulls.Endpoint(enterprise))
+}
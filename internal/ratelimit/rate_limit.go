@@ -1,30 +1,103 @@
 package ratelimit
 
 import (
+	"net/http"
+	"sync"
+	"time"
+
 	"github.com/benbjohnson/clock"
 	"github.com/chrisyxlee/snippets/internal"
 	"github.com/google/go-github/v53/github"
 )
 
+// How long to wait for a secondary rate limit that doesn't say when to retry.
+// GitHub asks for at least a minute.
+const defaultRetryAfter = time.Minute
+
 var clk = clock.New()
 
-// Set the clock to something else, say the mock clock, for tests.
+// The rate limit is shared by everything making requests with the same token, so
+// once any caller is rate limited, every caller waits until pausedUntil instead of
+// running into the limit on their own.
+var (
+	mu          sync.Mutex
+	pausedUntil time.Time
+)
+
+// Set the clock to something else, say the mock clock, for tests. This also
+// forgets any rate limit that was being waited out.
 func SetClock(c clock.Clock) {
+	mu.Lock()
+	defer mu.Unlock()
+
 	clk = c
+	pausedUntil = time.Time{}
 }
 
-// WaitIfRateLimited will return true if the error was a rate limit. If the error
-// was a rate limit, it will also block until the rate limit is cleared. If the
-// error was not a rate limit, then this will return false.
+// WaitIfRateLimited will return true if the error was a rate limit, either the
+// primary rate limit or a secondary (abuse) rate limit. If the error was a rate
+// limit, it will also block until the rate limit is cleared, and make every other
+// request through Transport wait too. If the error was not a rate limit, then this
+// will return false.
 func WaitIfRateLimited(err error) bool {
-	rlErr, ok := err.(*github.RateLimitError)
-	if !ok {
-		return false
+	switch rlErr := err.(type) {
+	case *github.RateLimitError:
+		if dur := clk.Until(rlErr.Rate.Reset.Time); dur > 0 {
+			internal.Log().Info().Dur("duration", dur).Time("time", rlErr.Rate.Reset.Time).Msg("waiting for rate limit to continue")
+			pause(rlErr.Rate.Reset.Time)
+		}
+		Wait()
+		return true
+	case *github.AbuseRateLimitError:
+		dur := defaultRetryAfter
+		if rlErr.RetryAfter != nil {
+			dur = *rlErr.RetryAfter
+		}
+		if dur > 0 {
+			internal.Log().Info().Dur("duration", dur).Msg("waiting for secondary rate limit to continue")
+			pause(clk.Now().Add(dur))
+		}
+		Wait()
+		return true
+	}
+
+	return false
+}
+
+// pause makes every request wait until the time, unless they already wait longer.
+func pause(until time.Time) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if until.After(pausedUntil) {
+		pausedUntil = until
+	}
+}
+
+// Wait blocks until the shared rate limit is cleared, if any caller was rate
+// limited.
+func Wait() {
+	mu.Lock()
+	c, until := clk, pausedUntil
+	mu.Unlock()
+
+	if dur := c.Until(until); dur > 0 {
+		c.Sleep(dur)
 	}
+}
+
+// Transport is an http.RoundTripper that waits for the shared rate limit to clear
+// before each request, so that concurrent requests don't keep running into it.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	Wait()
 
-	if dur := clk.Until(rlErr.Rate.Reset.Time); dur > 0 {
-		internal.Log().Info().Dur("duration", dur).Time("time", rlErr.Rate.Reset.Time).Msg("waiting for rate limit to continue")
-		clk.Sleep(dur)
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
 	}
-	return true
+	return base.RoundTrip(req)
 }
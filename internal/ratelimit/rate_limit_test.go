@@ -2,6 +2,8 @@ package ratelimit_test
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -53,4 +55,85 @@ func TestRateLimit(t *testing.T) {
 			},
 		}))
 	})
+
+	t.Run("secondary rate limited", func(t *testing.T) {
+		mc := clock.NewMock()
+		ratelimit.SetClock(mc)
+
+		retryAfter := time.Minute
+		assert.True(t, advanceUntilDone(mc, func() bool {
+			return ratelimit.WaitIfRateLimited(&github.AbuseRateLimitError{
+				RetryAfter: &retryAfter,
+			})
+		}))
+	})
+
+	t.Run("secondary rate limited without retry after", func(t *testing.T) {
+		mc := clock.NewMock()
+		ratelimit.SetClock(mc)
+
+		assert.True(t, advanceUntilDone(mc, func() bool {
+			return ratelimit.WaitIfRateLimited(&github.AbuseRateLimitError{})
+		}))
+	})
+}
+
+// roundTripFunc adapts a function into an http.RoundTripper.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Not parallel, since the rate limit and clock are shared by the whole package.
+func TestTransportSharesRateLimit(t *testing.T) {
+	mc := clock.NewMock()
+	ratelimit.SetClock(mc)
+	now := mc.Now()
+
+	waited := make(chan bool)
+	go func() {
+		waited <- ratelimit.WaitIfRateLimited(&github.RateLimitError{
+			Rate: github.Rate{Reset: github.Timestamp{Time: now.Add(5 * time.Minute)}},
+		})
+	}()
+
+	sent := make(chan time.Time)
+	transport := &ratelimit.Transport{Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent <- mc.Now()
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})}
+	go func() {
+		// Give the rate limited caller a chance to pause everyone first.
+		time.Sleep(10 * time.Millisecond)
+		_, _ = transport.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.github.com/user", nil))
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("request was sent while rate limited")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	mc.Add(5 * time.Minute)
+	assert.True(t, <-waited)
+	assert.Equal(t, now.Add(5*time.Minute), <-sent)
+}
+
+// advanceUntilDone keeps moving the mock clock forward until fn returns, since a
+// wait relative to now can't be cleared by setting the clock ahead of time.
+func advanceUntilDone(mc *clock.Mock, fn func() bool) bool {
+	done := make(chan bool)
+	go func() {
+		done <- fn()
+	}()
+
+	for {
+		select {
+		case ok := <-done:
+			return ok
+		case <-time.After(time.Millisecond):
+			mc.Add(time.Second)
+		}
+	}
 }
@@ -0,0 +1,192 @@
+// Package releases works out which release of a repository first shipped a
+// commit.
+package releases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/page"
+	"github.com/chrisyxlee/snippets/internal/ratelimit"
+	"github.com/google/go-github/v53/github"
+)
+
+// Finder finds the first release containing a commit. Releases and tags are listed
+// once per repository, and each commit is compared to the tags published after it
+// was merged until one contains it.
+type Finder struct {
+	ctx    context.Context
+	client *github.Client
+	// Published releases of each repository, including the tags without a release,
+	// oldest first.
+	releases map[string][]*github.RepositoryRelease
+}
+
+// NewFinder creates a Finder that looks up releases through the client.
+func NewFinder(ctx context.Context, client *github.Client) *Finder {
+	return &Finder{
+		ctx:      ctx,
+		client:   client,
+		releases: make(map[string][]*github.RepositoryRelease),
+	}
+}
+
+// First returns the earliest published release of the repository, formatted as
+// owner/name, whose tag contains the commit that was merged at the time. Tags
+// without a release count as released when their commit was committed. It returns
+// nil if no release has shipped the commit yet.
+func (f *Finder) First(repo string, sha string, mergedAt time.Time) (*github.RepositoryRelease, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("repository `%s` must be formatted as owner/name", repo)
+	}
+
+	releases, err := f.list(owner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, release := range releases {
+		// Releases published before the merge can't contain it.
+		if release.GetPublishedAt().Before(mergedAt) {
+			continue
+		}
+
+		contains, err := f.contains(owner, name, sha, release.GetTagName())
+		if err != nil {
+			return nil, err
+		}
+		if contains {
+			return release, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// list returns the published releases of the repository and its tags without a
+// release, oldest first.
+func (f *Finder) list(owner string, name string) ([]*github.RepositoryRelease, error) {
+	key := owner + "/" + name
+	if releases, ok := f.releases[key]; ok {
+		return releases, nil
+	}
+
+	var releases []*github.RepositoryRelease
+	err := page.Paginate("list releases", 100, func(opts github.ListOptions) (page.Details, *github.Response, error) {
+		res, resp, err := f.client.Repositories.ListReleases(f.ctx, owner, name, &opts)
+		if err != nil {
+			return page.Details{}, resp, err
+		}
+
+		for _, release := range res {
+			// Drafts haven't shipped anything.
+			if !release.GetDraft() && release.PublishedAt != nil {
+				releases = append(releases, release)
+			}
+		}
+		return page.Details{}, resp, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list releases of %s: %w", key, err)
+	}
+
+	tags, err := f.listTags(owner, name, releases)
+	if err != nil {
+		return nil, err
+	}
+	releases = append(releases, tags...)
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].GetPublishedAt().Before(releases[j].GetPublishedAt().Time)
+	})
+	internal.Log().Debug().Str("repository", key).Int("releases", len(releases)).Int("tags", len(tags)).
+		Msg("listed releases")
+
+	f.releases[key] = releases
+	return releases, nil
+}
+
+// listTags returns the tags of the repository that don't have one of the releases,
+// as releases published when the tagged commit was committed, since tags don't
+// record when they were pushed.
+func (f *Finder) listTags(owner string, name string, releases []*github.RepositoryRelease) ([]*github.RepositoryRelease, error) {
+	key := owner + "/" + name
+	released := make(map[string]bool, len(releases))
+	for _, release := range releases {
+		released[release.GetTagName()] = true
+	}
+
+	var tags []*github.RepositoryTag
+	err := page.Paginate("list tags", 100, func(opts github.ListOptions) (page.Details, *github.Response, error) {
+		res, resp, err := f.client.Repositories.ListTags(f.ctx, owner, name, &opts)
+		if err != nil {
+			return page.Details{}, resp, err
+		}
+
+		for _, tag := range res {
+			if !released[tag.GetName()] {
+				tags = append(tags, tag)
+			}
+		}
+		return page.Details{}, resp, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list tags of %s: %w", key, err)
+	}
+
+	out := make([]*github.RepositoryRelease, 0, len(tags))
+	for _, tag := range tags {
+		commit, err := f.commit(owner, name, tag.GetCommit().GetSHA())
+		if err != nil {
+			return nil, fmt.Errorf("tag %s of %s: %w", tag.GetName(), key, err)
+		}
+
+		out = append(out, &github.RepositoryRelease{
+			TagName:     tag.Name,
+			PublishedAt: commit.GetCommit().GetCommitter().Date,
+			// GitHub shows the tag on the page for its release, even without one.
+			HTMLURL: github.String(strings.TrimSuffix(commit.GetHTMLURL(), "/commit/"+commit.GetSHA()) +
+				"/releases/tag/" + tag.GetName()),
+		})
+	}
+
+	return out, nil
+}
+
+// commit gets the commit with the SHA.
+func (f *Finder) commit(owner string, name string, sha string) (*github.RepositoryCommit, error) {
+	for {
+		commit, _, err := f.client.Repositories.GetCommit(f.ctx, owner, name, sha, &github.ListOptions{PerPage: 1})
+		if ratelimit.WaitIfRateLimited(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get commit %s: %w", sha, err)
+		}
+
+		return commit, nil
+	}
+}
+
+// contains returns true if the tag contains the commit, which is when the tag is
+// identical to or ahead of the commit.
+func (f *Finder) contains(owner string, name string, sha string, tag string) (bool, error) {
+	for {
+		comparison, _, err := f.client.Repositories.CompareCommits(f.ctx, owner, name, sha, tag,
+			&github.ListOptions{PerPage: 1})
+		if ratelimit.WaitIfRateLimited(err) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("compare %s to %s in %s/%s: %w", sha, tag, owner, name, err)
+		}
+
+		status := comparison.GetStatus()
+		return status == "ahead" || status == "identical", nil
+	}
+}
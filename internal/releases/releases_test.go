@@ -0,0 +1,69 @@
+package releases_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/fakegithub"
+	"github.com/chrisyxlee/snippets/internal/releases"
+	"github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC)
+
+func TestFirst(t *testing.T) {
+	t.Parallel()
+
+	s := fakegithub.New("octocat")
+	t.Cleanup(s.Close)
+	s.AddReleases(
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.3.0", PublishedAt: start, Commits: []string{"old"}},
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.5.0", PublishedAt: start.AddDate(0, 0, 7),
+			Commits: []string{"old", "merged", "later"}},
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.4.0", PublishedAt: start.AddDate(0, 0, 3),
+			Commits: []string{"old", "merged"}},
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v1.4.1", PublishedAt: start.AddDate(0, 0, 5), TagOnly: true,
+			Commits: []string{"old", "merged", "hotfix"}},
+		&fakegithub.Release{Repo: "octo/widgets", Tag: "v2.0.0-draft", Draft: true,
+			Commits: []string{"old", "merged", "later", "unreleased"}},
+	)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(s.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+
+	f := releases.NewFinder(context.Background(), client)
+
+	release, err := f.First("octo/widgets", "merged", start.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.NotNil(t, release)
+	assert.Equal(t, "v1.4.0", release.GetTagName())
+
+	release, err = f.First("octo/widgets", "later", start.AddDate(0, 0, 4))
+	require.NoError(t, err)
+	require.NotNil(t, release)
+	assert.Equal(t, "v1.5.0", release.GetTagName())
+
+	// Tags without a release count as released when the tagged commit was committed.
+	release, err = f.First("octo/widgets", "hotfix", start.AddDate(0, 0, 4))
+	require.NoError(t, err)
+	require.NotNil(t, release)
+	assert.Equal(t, "v1.4.1", release.GetTagName())
+	assert.Equal(t, "https://github.com/octo/widgets/releases/tag/v1.4.1", release.GetHTMLURL())
+	assert.True(t, release.GetPublishedAt().Time.Equal(start.AddDate(0, 0, 5)))
+
+	requests := s.Requests()
+	release, err = f.First("octo/widgets", "unreleased", start.AddDate(0, 0, 8))
+	require.NoError(t, err)
+	assert.Nil(t, release)
+	// The releases were already listed, and none were published after the merge.
+	assert.Equal(t, requests, s.Requests())
+
+	_, err = f.First("widgets", "merged", start)
+	assert.ErrorContains(t, err, "owner/name")
+}
@@ -0,0 +1,251 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ErrNotRecorded is returned when replaying a request that isn't in the fixture.
+var ErrNotRecorded = errors.New("request was not recorded")
+
+// Interaction is a single request and the response to it.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// Bodies are JSON, so they're kept as is to make the fixtures easier to read
+	// and edit.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int               `json:"status_code"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+}
+
+// Only these response headers are recorded, since the rest are either irrelevant
+// or identify the account that made the recording.
+var recordedHeaders = []string{"Content-Type", "ETag", "Link"}
+
+// Anything that looks like a GitHub token is scrubbed from the recording, in case
+// it was echoed back by the API.
+var tokenPattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{30,}|github_pat_[A-Za-z0-9_]{30,})\b`)
+
+const scrubbed = "REDACTED"
+
+// Load reads the interactions from a fixture file.
+func Load(path string) ([]Interaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var interactions []Interaction
+	if err = json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("parse fixture %s: %w", path, err)
+	}
+
+	return interactions, nil
+}
+
+// Recorder is an http.RoundTripper that records every interaction so that they
+// can be saved as a fixture for Replayer.
+type Recorder struct {
+	// Transport that sends the requests, http.DefaultTransport if nil.
+	Base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, req, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	res, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	header := make(map[string]string)
+	for _, name := range recordedHeaders {
+		if value := res.Header.Get(name); len(value) > 0 {
+			header[name] = scrub(value)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    scrub(req.URL.String()),
+			Body:   rawJSON(body),
+		},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     header,
+			Body:       rawJSON(resBody),
+		},
+	})
+
+	return res, nil
+}
+
+// Save writes the recorded interactions to the fixture file.
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode fixture: %w", err)
+	}
+
+	if err = os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write fixture: %w", err)
+	}
+
+	return nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from recorded
+// interactions without touching the network. Requests are matched by method, URL,
+// and body. If the same request was recorded more than once, the responses are
+// replayed in order, and the last one is repeated once they run out.
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]Response
+}
+
+// NewReplayer creates a Replayer for the interactions.
+func NewReplayer(interactions []Interaction) *Replayer {
+	r := &Replayer{responses: make(map[string][]Response)}
+	for _, i := range interactions {
+		key := matchKey(i.Request.Method, i.Request.URL, i.Request.Body)
+		r.responses[key] = append(r.responses[key], i.Response)
+	}
+
+	return r
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, req, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	key := matchKey(req.Method, req.URL.String(), body)
+	responses := r.responses[key]
+	if len(responses) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL, ErrNotRecorded)
+	}
+	res := responses[0]
+	if len(responses) > 1 {
+		r.responses[key] = responses[1:]
+	}
+	r.mu.Unlock()
+
+	header := make(http.Header)
+	for name, value := range res.Header {
+		header.Set(name, value)
+	}
+
+	body = res.Body
+	var s string
+	if !strings.Contains(header.Get("Content-Type"), "json") && json.Unmarshal(body, &s) == nil {
+		body = []byte(s)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+		StatusCode:    res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// matchKey identifies a request regardless of how its JSON body is formatted or
+// which order its keys are in.
+func matchKey(method string, url string, body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if normalized, err := json.Marshal(v); err == nil {
+			body = normalized
+		}
+	}
+
+	return strings.Join([]string{method, url, string(body)}, "\n")
+}
+
+// readRequestBody reads the body of the request, and returns the request with its
+// body restored.
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, req, nil
+}
+
+func scrub(s string) string {
+	return tokenPattern.ReplaceAllString(s, scrubbed)
+}
+
+// rawJSON scrubs the body and keeps it as JSON. Bodies that aren't JSON are kept as
+// a JSON string instead, and are unquoted when replayed.
+func rawJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	body = []byte(scrub(string(body)))
+	if json.Valid(body) {
+		return body
+	}
+
+	s, _ := json.Marshal(string(body))
+	return s
+}
@@ -0,0 +1,170 @@
+// Package review lets the user triage the items in a report before publishing it,
+// and remembers their decisions for the next report over the same window.
+package review
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/output"
+	"github.com/samber/lo"
+)
+
+// Decision is what the user decided about a single item.
+type Decision struct {
+	// Section that the item was moved to, or empty to leave it where the rules
+	// placed it.
+	Section string `json:"section,omitempty"`
+	// Hidden items are left out of the report.
+	Hidden    bool   `json:"hidden,omitempty"`
+	Highlight bool   `json:"highlight,omitempty"`
+	Note      string `json:"note,omitempty"`
+}
+
+func (d *Decision) empty() bool {
+	return d == nil || *d == Decision{}
+}
+
+// Decisions are the user's decisions for a report, keyed by the section that the
+// rules placed each item in and its HTML URL, i.e.
+// "Merged https://github.com/octo/widgets/pull/12".
+type Decisions struct {
+	Items map[string]*Decision `json:"items"`
+}
+
+// key identifies the item in the section that the rules placed it in, since the
+// multi-match policy can place the same item in several sections.
+func key(placed string, item *format.Item) string {
+	return placed + " " + item.HTMLURL
+}
+
+// DefaultDir is where decisions are saved unless otherwise specified:
+// $XDG_STATE_HOME/snippets/reviews, or ~/.local/state/snippets/reviews if
+// XDG_STATE_HOME isn't set.
+func DefaultDir() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if len(dir) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(dir, "snippets", "reviews")
+}
+
+// Path is the file that the decisions for the user's report over [start, end) are
+// saved in.
+func Path(dir string, user string, start time.Time, end time.Time) string {
+	return filepath.Join(dir, user, output.FileName(start, end, "", ".json"))
+}
+
+// Load reads the decisions saved at the path. No decisions are returned if
+// nothing was saved yet.
+func Load(path string) (*Decisions, error) {
+	d := &Decisions{Items: map[string]*Decision{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("parse review decisions %s: %w", path, err)
+	}
+	if d.Items == nil {
+		d.Items = map[string]*Decision{}
+	}
+
+	return d, nil
+}
+
+// Save writes the decisions to the path, leaving out items without any decisions.
+func (d *Decisions) Save(path string) error {
+	items := lo.OmitBy(d.Items, func(_ string, decision *Decision) bool {
+		return decision.empty()
+	})
+
+	data, err := json.MarshalIndent(&Decisions{Items: items}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create review directory: %w", err)
+	}
+
+	return output.WriteFile(path, append(data, '\n'), true)
+}
+
+// Get returns the decision for the item that the rules placed in the section,
+// creating it if there isn't one yet.
+func (d *Decisions) Get(placed string, item *format.Item) *Decision {
+	decision, ok := d.Items[key(placed, item)]
+	if !ok || decision == nil {
+		decision = &Decision{}
+		d.Items[key(placed, item)] = decision
+	}
+
+	return decision
+}
+
+// sectionOf returns the title of the section that the item ends up in.
+func (d *Decisions) sectionOf(r *format.Report, item *format.Item, placed string) string {
+	decision := d.Items[key(placed, item)]
+	if decision.empty() || len(decision.Section) == 0 {
+		return placed
+	}
+
+	// Sections can disappear when the rules change, so the item stays put.
+	if !lo.ContainsBy(r.Sections, func(s *format.Section) bool { return s.Title == decision.Section }) {
+		return placed
+	}
+
+	return decision.Section
+}
+
+// Apply returns a copy of the report with the decisions applied: items are moved
+// between sections, hidden items are left out, and highlights and notes are set.
+// Items that were moved keep their relative order in the report, and are left out
+// of sections that already list them.
+func (d *Decisions) Apply(r *format.Report) *format.Report {
+	out := *r
+	out.Sections = lo.Map(r.Sections, func(s *format.Section, _ int) *format.Section {
+		return &format.Section{Title: s.Title, Items: []*format.Item{}}
+	})
+
+	for _, section := range r.Sections {
+		for _, item := range section.Items {
+			decision := d.Items[key(section.Title, item)]
+			if !decision.empty() && decision.Hidden {
+				continue
+			}
+
+			copied := *item
+			if !decision.empty() {
+				copied.Highlight = decision.Highlight
+				copied.Note = decision.Note
+			}
+
+			title := d.sectionOf(r, item, section.Title)
+			target, _ := lo.Find(out.Sections, func(s *format.Section) bool { return s.Title == title })
+			if lo.ContainsBy(target.Items, func(other *format.Item) bool { return other.HTMLURL == item.HTMLURL }) {
+				continue
+			}
+			target.Items = append(target.Items, &copied)
+		}
+	}
+
+	return &out
+}
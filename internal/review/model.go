@@ -0,0 +1,241 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/samber/lo"
+)
+
+var (
+	styleTitle   = lipgloss.NewStyle().Bold(true)
+	styleSection = lipgloss.NewStyle().Bold(true).Underline(true)
+	styleCursor  = lipgloss.NewStyle().Reverse(true)
+	styleHidden  = lipgloss.NewStyle().Faint(true).Strikethrough(true)
+	styleHelp    = lipgloss.NewStyle().Faint(true)
+	stylePreview = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+)
+
+const help = "↑/↓ select • ←/→ move to section • x hide • s star • n note • p preview • q save and quit • ctrl+c discard"
+
+// row is an item listed under the section that it ends up in.
+type row struct {
+	item *format.Item
+	// Section that the rules placed the item in.
+	placed  string
+	section string
+}
+
+// Model is the Bubble Tea model for reviewing a report. The report is left as is,
+// and every change is recorded in the decisions.
+type Model struct {
+	report    *format.Report
+	decisions *Decisions
+	// Items in the sections that the rules placed them in.
+	placed []row
+	rows   []row
+	cursor int
+
+	preview bool
+	editing bool
+	note    []rune
+	saved   bool
+}
+
+// NewModel creates a model for reviewing the report, starting from the decisions
+// that were made before.
+func NewModel(r *format.Report, d *Decisions) *Model {
+	m := &Model{report: r, decisions: d}
+	for _, section := range r.Sections {
+		for _, item := range section.Items {
+			m.placed = append(m.placed, row{item: item, placed: section.Title, section: section.Title})
+		}
+	}
+	m.layout()
+
+	return m
+}
+
+// Saved returns true if the user quit by saving their decisions, rather than
+// discarding them.
+func (m *Model) Saved() bool {
+	return m.saved
+}
+
+// Decisions returns the decisions that the user made.
+func (m *Model) Decisions() *Decisions {
+	return m.decisions
+}
+
+// layout lists the items by the section that they end up in, keeping the cursor
+// on the same item.
+func (m *Model) layout() {
+	var selected *row
+	if m.cursor < len(m.rows) {
+		selected = &m.rows[m.cursor]
+	}
+
+	var rows []row
+	for _, section := range m.report.Sections {
+		for _, p := range m.placed {
+			if m.decisions.sectionOf(m.report, p.item, p.placed) == section.Title {
+				rows = append(rows, row{item: p.item, placed: p.placed, section: section.Title})
+			}
+		}
+	}
+
+	if selected != nil {
+		_, m.cursor, _ = lo.FindIndexOf(rows, func(r row) bool {
+			return r.item == selected.item && r.placed == selected.placed
+		})
+	}
+	m.rows = rows
+}
+
+// move moves the selected item by delta sections.
+func (m *Model) move(delta int) {
+	current := m.rows[m.cursor]
+	titles := lo.Map(m.report.Sections, func(s *format.Section, _ int) string { return s.Title })
+	i := lo.IndexOf(titles, current.section) + delta
+	if i < 0 || i >= len(titles) {
+		return
+	}
+
+	decision := m.decisions.Get(current.placed, current.item)
+	decision.Section = titles[i]
+	if decision.Section == current.placed {
+		decision.Section = ""
+	}
+	m.layout()
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.editing {
+		switch key.Type {
+		case tea.KeyEnter:
+			current := m.rows[m.cursor]
+			m.decisions.Get(current.placed, current.item).Note = strings.TrimSpace(string(m.note))
+			m.editing = false
+		case tea.KeyEsc:
+			m.editing = false
+		case tea.KeyBackspace:
+			if len(m.note) > 0 {
+				m.note = m.note[:len(m.note)-1]
+			}
+		case tea.KeySpace:
+			m.note = append(m.note, ' ')
+		case tea.KeyRunes:
+			m.note = append(m.note, key.Runes...)
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	switch key.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "q":
+		m.saved = true
+		return m, tea.Quit
+	case "p":
+		m.preview = !m.preview
+	}
+
+	if len(m.rows) == 0 {
+		return m, nil
+	}
+
+	current := m.rows[m.cursor]
+	switch key.String() {
+	case "up", "k":
+		m.cursor = lo.Max([]int{m.cursor - 1, 0})
+	case "down", "j":
+		m.cursor = lo.Min([]int{m.cursor + 1, len(m.rows) - 1})
+	case "left", "h":
+		m.move(-1)
+	case "right", "l":
+		m.move(1)
+	case "x":
+		decision := m.decisions.Get(current.placed, current.item)
+		decision.Hidden = !decision.Hidden
+	case "s":
+		decision := m.decisions.Get(current.placed, current.item)
+		decision.Highlight = !decision.Highlight
+	case "n":
+		m.editing = true
+		m.note = []rune(m.decisions.Get(current.placed, current.item).Note)
+	}
+
+	return m, nil
+}
+
+func (m *Model) View() string {
+	var b strings.Builder
+	b.WriteString(styleTitle.Render(fmt.Sprintf("Reviewing %s's report for %s",
+		m.report.User, m.report.Window.Start.Format("2006-01-02"))))
+	b.WriteString("\n")
+
+	for _, section := range m.report.Sections {
+		rows := lo.Filter(m.rows, func(r row, _ int) bool { return r.section == section.Title })
+		b.WriteString("\n")
+		b.WriteString(styleSection.Render(fmt.Sprintf("%s (%d)", section.Title, len(rows))))
+		b.WriteString("\n")
+		for _, r := range rows {
+			b.WriteString(m.viewRow(r))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if m.editing {
+		b.WriteString(fmt.Sprintf("Note: %s█\n", string(m.note)))
+		b.WriteString(styleHelp.Render("enter save note • esc cancel"))
+	} else {
+		b.WriteString(styleHelp.Render(help))
+	}
+
+	if !m.preview {
+		return b.String()
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, b.String(), "  ",
+		stylePreview.Render(strings.TrimSpace(format.RenderMarkdown(m.decisions.Apply(m.report)))))
+}
+
+func (m *Model) viewRow(r row) string {
+	decision := m.decisions.Items[key(r.placed, r.item)]
+	if decision == nil {
+		decision = &Decision{}
+	}
+
+	star := "  "
+	if decision.Highlight {
+		star = "⭐"
+	}
+
+	line := fmt.Sprintf("%s %s %s", format.Ref(r.item, true), format.Kind(r.item), r.item.Title)
+	if len(decision.Note) > 0 {
+		line += " — " + decision.Note
+	}
+	if decision.Hidden {
+		line = styleHidden.Render(line + " (hidden)")
+	}
+	if current := m.rows[m.cursor]; current.item == r.item && current.placed == r.placed {
+		line = styleCursor.Render(line)
+	}
+
+	return fmt.Sprintf("%s %s", star, line)
+}
@@ -0,0 +1,219 @@
+package review_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/review"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	start = time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC)
+	end   = start.AddDate(0, 0, 14)
+)
+
+func item(number int, title string) *format.Item {
+	return &format.Item{
+		Type:       format.ItemTypePullRequest,
+		Number:     number,
+		Repository: "octo/widgets",
+		Title:      title,
+		HTMLURL:    "https://github.com/octo/widgets/pull/" + string(rune('0'+number)),
+	}
+}
+
+func testReport() *format.Report {
+	r := format.NewReport("octocat", start, end)
+	r.Sections = []*format.Section{
+		{Title: "Merged", Items: []*format.Item{item(1, "Add sprockets"), item(2, "Oil the gears")}},
+		{Title: "Open", Items: []*format.Item{item(3, "Paint the widgets")}},
+	}
+	return r
+}
+
+// titles lists the titles of the items in each section.
+func titles(r *format.Report) map[string][]string {
+	return lo.SliceToMap(r.Sections, func(s *format.Section) (string, []string) {
+		return s.Title, lo.Map(s.Items, func(item *format.Item, _ int) string { return item.Title })
+	})
+}
+
+func TestPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.Join("reviews", "octocat", "2026-10-05_2026-10-18.json"),
+		review.Path("reviews", "octocat", start, end))
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "octocat", "review.json")
+	d, err := review.Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, d.Items)
+
+	d.Get("Merged", item(1, "")).Note = "Unblocked the launch"
+	// Items without any decisions aren't saved.
+	d.Get("Merged", item(2, ""))
+	require.NoError(t, d.Save(path))
+
+	d, err = review.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*review.Decision{
+		"Merged " + item(1, "").HTMLURL: {Note: "Unblocked the launch"},
+	}, d.Items)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err = review.Load(path)
+	assert.ErrorContains(t, err, "parse review decisions")
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	r := testReport()
+	d := &review.Decisions{Items: map[string]*review.Decision{
+		"Merged " + item(1, "").HTMLURL: {Highlight: true, Note: "Unblocked the launch"},
+		"Merged " + item(2, "").HTMLURL: {Section: "Open"},
+		"Open " + item(3, "").HTMLURL:   {Hidden: true},
+	}}
+
+	applied := d.Apply(r)
+	assert.Equal(t, map[string][]string{
+		"Merged": {"Add sprockets"},
+		"Open":   {"Oil the gears"},
+	}, titles(applied))
+	assert.True(t, applied.Sections[0].Items[0].Highlight)
+	assert.Equal(t, "Unblocked the launch", applied.Sections[0].Items[0].Note)
+
+	// The original report is left alone.
+	assert.Equal(t, map[string][]string{
+		"Merged": {"Add sprockets", "Oil the gears"},
+		"Open":   {"Paint the widgets"},
+	}, titles(r))
+	assert.False(t, r.Sections[0].Items[0].Highlight)
+
+	// Items moved to a section that no longer exists stay where they are.
+	d.Items["Merged "+item(2, "").HTMLURL].Section = "Gone"
+	assert.Equal(t, []string{"Add sprockets", "Oil the gears"}, titles(d.Apply(r))["Merged"])
+}
+
+func TestApplyMultiMatch(t *testing.T) {
+	t.Parallel()
+
+	// The multi-match policy places the same item in several sections, which are
+	// decided on separately.
+	r := testReport()
+	r.Sections[1].Items = append(r.Sections[1].Items, item(1, "Add sprockets"))
+	d := &review.Decisions{Items: map[string]*review.Decision{
+		"Merged " + item(1, "").HTMLURL: {Highlight: true},
+		"Merged " + item(2, "").HTMLURL: {Section: "Open"},
+		"Open " + item(1, "").HTMLURL:   {Section: "Merged"},
+	}}
+
+	applied := d.Apply(r)
+	assert.Equal(t, map[string][]string{
+		"Merged": {"Add sprockets"},
+		"Open":   {"Oil the gears", "Paint the widgets"},
+	}, titles(applied))
+	assert.True(t, applied.Sections[0].Items[0].Highlight)
+
+	d.Items["Open "+item(1, "").HTMLURL] = &review.Decision{Hidden: true}
+	assert.Equal(t, map[string][]string{
+		"Merged": {"Add sprockets"},
+		"Open":   {"Oil the gears", "Paint the widgets"},
+	}, titles(d.Apply(r)))
+}
+
+func press(t *testing.T, m *review.Model, keys ...string) tea.Cmd {
+	var cmd tea.Cmd
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "up", "down", "left", "right", "enter", "esc", "ctrl+c", "backspace", " ":
+			msg = tea.KeyMsg{Type: map[string]tea.KeyType{
+				"up": tea.KeyUp, "down": tea.KeyDown, "left": tea.KeyLeft, "right": tea.KeyRight,
+				"enter": tea.KeyEnter, "esc": tea.KeyEsc, "ctrl+c": tea.KeyCtrlC,
+				"backspace": tea.KeyBackspace, " ": tea.KeySpace,
+			}[key]}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		_, cmd = m.Update(msg)
+	}
+	return cmd
+}
+
+func TestModel(t *testing.T) {
+	t.Parallel()
+
+	r := testReport()
+	m := review.NewModel(r, &review.Decisions{Items: map[string]*review.Decision{}})
+	assert.Contains(t, m.View(), "Merged (2)")
+
+	// Move the second item to the open section, where the cursor follows it.
+	press(t, m, "down", "right", "right", "s")
+	// Hide the first item and give the third a note, with a typo.
+	press(t, m, "up", "up", "up", "x", "down", "down", "n", "S", "o", "x", "backspace", " ", "o", "k", "enter")
+	// Notes can be abandoned.
+	press(t, m, "n", "!", "esc")
+
+	view := m.View()
+	assert.Contains(t, view, "Merged (1)")
+	assert.Contains(t, view, "Open (2)")
+	assert.Contains(t, view, "(hidden)")
+	assert.Contains(t, view, "— So ok")
+
+	assert.Equal(t, map[string]*review.Decision{
+		"Merged " + item(1, "").HTMLURL: {Hidden: true},
+		"Merged " + item(2, "").HTMLURL: {Section: "Open", Highlight: true},
+		"Open " + item(3, "").HTMLURL:   {Note: "So ok"},
+	}, m.Decisions().Items)
+
+	// Moving an item back to where the rules placed it forgets the move.
+	press(t, m, "up", "left")
+	assert.Equal(t, "", m.Decisions().Items["Merged "+item(2, "").HTMLURL].Section)
+
+	press(t, m, "p")
+	assert.Contains(t, m.View(), "- ⭐ PR [octo/widgets#2](https://github.com/octo/widgets/pull/2) - Oil the gears")
+
+	assert.NotNil(t, press(t, m, "q"))
+	assert.True(t, m.Saved())
+}
+
+func TestModelKinds(t *testing.T) {
+	t.Parallel()
+
+	r := format.NewReport("octocat", start, end)
+	r.Sections = []*format.Section{{Title: "Direct commits", Items: []*format.Item{
+		format.NewCommitItem("octo/widgets", &format.Commit{
+			SHA:     "abc1234def",
+			Subject: "Fix the docs",
+			HTMLURL: "https://github.com/octo/widgets/commit/abc1234def",
+		}),
+		format.NewReleaseItem("octo/widgets", &format.Release{
+			Tag:     "v1.4.0",
+			HTMLURL: "https://github.com/octo/widgets/releases/tag/v1.4.0",
+		}),
+	}}}
+	view := review.NewModel(r, &review.Decisions{Items: map[string]*review.Decision{}}).View()
+	assert.Contains(t, view, "octo/widgets@abc1234 Commit Fix the docs")
+	assert.Contains(t, view, "octo/widgets@v1.4.0 Release v1.4.0")
+	assert.NotContains(t, view, "#0")
+}
+
+func TestModelDiscard(t *testing.T) {
+	t.Parallel()
+
+	m := review.NewModel(testReport(), &review.Decisions{Items: map[string]*review.Decision{}})
+	assert.NotNil(t, press(t, m, "x", "ctrl+c"))
+	assert.False(t, m.Saved())
+}
@@ -0,0 +1,481 @@
+// Package rules classifies issues and pull requests into report sections with a
+// declarative set of rules.
+package rules
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/util"
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy decides what happens when more than one rule matches an item.
+type Policy string
+
+const (
+	// The item goes into the section of the first rule that matches.
+	PolicyFirstMatch Policy = "first-match"
+	// The item goes into the sections of every rule that matches.
+	PolicyMultiMatch Policy = "multi-match"
+)
+
+// Roles that the user can have on an item.
+const (
+	RoleAuthor    = "author"
+	RoleReviewer  = "reviewer"
+	RoleAssignee  = "assignee"
+	RoleCommenter = "commenter"
+)
+
+var roles = []string{RoleAuthor, RoleReviewer, RoleAssignee, RoleCommenter}
+
+// When a date is relative to the report window.
+const (
+	WhenWithin = "within"
+	WhenBefore = "before"
+	WhenAfter  = "after"
+	// Only for closed, for items that are still open.
+	WhenNever = "never"
+)
+
+//go:embed builtin/*.yaml
+var builtinRules embed.FS
+
+const rulesExt = ".yaml"
+
+// DefaultName is the built-in rule set used when none is configured.
+const DefaultName = "end-goal"
+
+// RuleSet is an ordered list of rules placing items into sections.
+type RuleSet struct {
+	// first-match (the default) or multi-match.
+	Policy Policy `yaml:"policy"`
+	// Section for items that no rule matches. Items are left out of the report if
+	// it's empty.
+	Default string `yaml:"default"`
+	// Optional details about the sections, keyed by their title.
+	Sections []*SectionInfo `yaml:"sections"`
+	Rules    []*Rule        `yaml:"rules"`
+}
+
+// SectionInfo describes a section beyond its title.
+type SectionInfo struct {
+	Title string `yaml:"title"`
+	// How the section reads in the summary sentence at the top of the report.
+	// Sections without a summary are left out of the sentence.
+	Summary *Summary `yaml:"summary"`
+}
+
+// Summary is a phrase with a %d verb for the number of items in the section, i.e.
+// "filed %d new issues".
+type Summary struct {
+	// Phrase for a single item.
+	One string `yaml:"one"`
+	// Phrase for any other number of items.
+	Other string `yaml:"other"`
+}
+
+// Rule places the items that it matches into a section.
+type Rule struct {
+	// Name shown by --explain, defaults to the section.
+	Name    string `yaml:"name"`
+	Section string `yaml:"section"`
+	Match   Match  `yaml:"match"`
+}
+
+// Match is a set of conditions that all have to hold for an item. Conditions that
+// are left empty always hold.
+type Match struct {
+	// issue or pull_request.
+	Type string `yaml:"type"`
+	// open or closed.
+	State string `yaml:"state"`
+	// completed, not_planned, or reopened.
+	StateReason string `yaml:"state_reason"`
+	Merged      *bool  `yaml:"merged"`
+	// When the item was created relative to the window, within, before, or after.
+	Created string `yaml:"created"`
+	// When the item was closed relative to the window, within, before, after, or
+	// never.
+	Closed string `yaml:"closed"`
+	// The item has any of these labels.
+	Labels []string `yaml:"labels"`
+	// The item is in any of these repositories, formatted as owner/name.
+	Repos []string `yaml:"repos"`
+	// The user has any of these roles on the item: author, reviewer, assignee, or
+	// commenter.
+	Roles []string `yaml:"roles"`
+	// The user commented on the item within the window.
+	Commented *bool `yaml:"commented"`
+	// None of these conditions hold.
+	Not *Match `yaml:"not"`
+}
+
+// Names returns the names of the built-in rule sets.
+func Names() []string {
+	entries, err := builtinRules.ReadDir("builtin")
+	if err != nil {
+		return nil
+	}
+
+	names := lo.Map(entries, func(entry os.DirEntry, _ int) string {
+		return strings.TrimSuffix(entry.Name(), rulesExt)
+	})
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the rules used when none are configured.
+func Default() *RuleSet {
+	rs, err := Load(DefaultName)
+	if err != nil {
+		panic(fmt.Sprintf("load default rules: %s", err))
+	}
+
+	return rs
+}
+
+// Load reads and validates the built-in rule set with the name, or otherwise the
+// YAML file at the path.
+func Load(nameOrPath string) (*RuleSet, error) {
+	if lo.Contains(Names(), nameOrPath) {
+		data, err := builtinRules.ReadFile("builtin/" + nameOrPath + rulesExt)
+		if err != nil {
+			return nil, err
+		}
+		return Parse(data)
+	}
+
+	data, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		return nil, fmt.Errorf("read rules (or use one of the built-in rules: %s): %w",
+			strings.Join(Names(), ", "), err)
+	}
+
+	return Parse(data)
+}
+
+// Parse parses and validates a rule set. Unknown keys are an error, since a typo
+// in a condition would otherwise match everything.
+func Parse(data []byte) (*RuleSet, error) {
+	var rs RuleSet
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&rs); err != nil {
+		return nil, fmt.Errorf("parse rules: %w", err)
+	}
+
+	if errs := rs.Validate(); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return &rs, nil
+}
+
+// Validate reports every problem with the rule set.
+func (rs *RuleSet) Validate() []error {
+	var errs []error
+	if len(rs.Policy) > 0 && rs.Policy != PolicyFirstMatch && rs.Policy != PolicyMultiMatch {
+		errs = append(errs, fmt.Errorf("unknown policy `%s`, expected %s or %s", rs.Policy, PolicyFirstMatch, PolicyMultiMatch))
+	}
+
+	if len(rs.Rules) == 0 {
+		errs = append(errs, fmt.Errorf("no rules"))
+	}
+
+	titles := rs.SectionTitles()
+	for i, info := range rs.Sections {
+		switch {
+		case info == nil:
+			errs = append(errs, fmt.Errorf("sections[%d]: empty section", i))
+			continue
+		case !lo.Contains(titles, info.Title):
+			errs = append(errs, fmt.Errorf("sections[%d]: no rule places items in `%s`", i, info.Title))
+		}
+		if info.Summary != nil {
+			for _, phrase := range []string{info.Summary.One, info.Summary.Other} {
+				if strings.Count(phrase, "%d") != 1 {
+					errs = append(errs, fmt.Errorf("sections[%d] (%s): summary `%s` must have exactly one %%d", i, info.Title, phrase))
+				}
+			}
+		}
+	}
+
+	for i, rule := range rs.Rules {
+		if rule == nil {
+			errs = append(errs, fmt.Errorf("rules[%d]: empty rule", i))
+			continue
+		}
+		if len(rule.Section) == 0 {
+			errs = append(errs, fmt.Errorf("rules[%d]: no section", i))
+		}
+		for _, err := range rule.Match.validate() {
+			errs = append(errs, fmt.Errorf("rules[%d] (%s): %w", i, rule.name(), err))
+		}
+	}
+
+	return errs
+}
+
+func (m *Match) validate() []error {
+	var errs []error
+	check := func(field string, value string, allowed ...string) {
+		if len(value) > 0 && !lo.Contains(allowed, value) {
+			errs = append(errs, fmt.Errorf("unknown %s `%s`, expected one of [%s]", field, value, strings.Join(allowed, ", ")))
+		}
+	}
+
+	check("type", m.Type, format.ItemTypeIssue, format.ItemTypePullRequest)
+	check("state", m.State, "open", "closed")
+	check("state_reason", m.StateReason, "completed", "not_planned", "reopened")
+	check("created", m.Created, WhenWithin, WhenBefore, WhenAfter)
+	check("closed", m.Closed, WhenWithin, WhenBefore, WhenAfter, WhenNever)
+	for _, role := range m.Roles {
+		check("role", role, roles...)
+	}
+	for _, repo := range m.Repos {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || len(owner) == 0 || len(name) == 0 {
+			errs = append(errs, fmt.Errorf("repo `%s` must be formatted as owner/name", repo))
+		}
+	}
+
+	if m.Not != nil {
+		for _, err := range m.Not.validate() {
+			errs = append(errs, fmt.Errorf("not: %w", err))
+		}
+	}
+
+	return errs
+}
+
+// Summarize describes each non-empty section of the report that has a summary, in
+// order, i.e. "filed 2 new issues".
+func (rs *RuleSet) Summarize(sections []*format.Section) []string {
+	return lo.FilterMap(sections, func(s *format.Section, _ int) (string, bool) {
+		info, ok := lo.Find(rs.Sections, func(info *SectionInfo) bool { return info.Title == s.Title })
+		if !ok || info.Summary == nil || len(s.Items) == 0 {
+			return "", false
+		}
+
+		if len(s.Items) == 1 {
+			return fmt.Sprintf(info.Summary.One, len(s.Items)), true
+		}
+		return fmt.Sprintf(info.Summary.Other, len(s.Items)), true
+	})
+}
+
+// SectionTitles returns the titles of the sections that the rules can place items
+// in, in the order that they first appear, followed by the default section.
+func (rs *RuleSet) SectionTitles() []string {
+	titles := lo.Uniq(lo.Map(rs.Rules, func(rule *Rule, _ int) string {
+		return rule.Section
+	}))
+	if len(rs.Default) > 0 && !lo.Contains(titles, rs.Default) {
+		titles = append(titles, rs.Default)
+	}
+
+	return titles
+}
+
+func (r *Rule) name() string {
+	if len(r.Name) > 0 {
+		return r.Name
+	}
+
+	return r.Section
+}
+
+// Env is what the items are judged against.
+type Env struct {
+	// Login of the user that the report is for.
+	User  string
+	Start time.Time
+	End   time.Time
+}
+
+// Placement records why an item was placed into a section.
+type Placement struct {
+	Issue   *format.GitHubIssue
+	Section string
+	// Name of the rule that matched, or empty if the item went to the default
+	// section.
+	Rule string
+}
+
+// Section is the items that were placed into a section, in their original order.
+type Section struct {
+	Title  string
+	Issues []*format.GitHubIssue
+}
+
+// Classify places each item into sections. Every section that the rules mention is
+// returned, even if it's empty, in the order of SectionTitles.
+func (rs *RuleSet) Classify(env Env, issues []*format.GitHubIssue) ([]*Section, []*Placement) {
+	sections := lo.Map(rs.SectionTitles(), func(title string, _ int) *Section {
+		return &Section{Title: title, Issues: []*format.GitHubIssue{}}
+	})
+	place := func(p *Placement) {
+		s, _ := lo.Find(sections, func(s *Section) bool { return s.Title == p.Section })
+		if !lo.Contains(s.Issues, p.Issue) {
+			s.Issues = append(s.Issues, p.Issue)
+		}
+	}
+
+	var placements []*Placement
+	for _, ghi := range issues {
+		var matched bool
+		for _, rule := range rs.Rules {
+			if !rule.Match.matches(env, ghi) {
+				continue
+			}
+
+			matched = true
+			p := &Placement{Issue: ghi, Section: rule.Section, Rule: rule.name()}
+			placements = append(placements, p)
+			place(p)
+			if rs.Policy != PolicyMultiMatch {
+				break
+			}
+		}
+
+		if !matched && len(rs.Default) > 0 {
+			p := &Placement{Issue: ghi, Section: rs.Default}
+			placements = append(placements, p)
+			place(p)
+		}
+	}
+
+	return sections, placements
+}
+
+func (m *Match) matches(env Env, ghi *format.GitHubIssue) bool {
+	issue := ghi.Issue
+	if len(m.Type) > 0 && m.Type != itemType(issue) {
+		return false
+	}
+	if len(m.State) > 0 && m.State != issue.GetState() {
+		return false
+	}
+	if len(m.StateReason) > 0 && m.StateReason != issue.GetStateReason() {
+		return false
+	}
+	if m.Merged != nil && *m.Merged != ghi.Merged {
+		return false
+	}
+	if len(m.Created) > 0 && m.Created != when(issue.CreatedAt, env) {
+		return false
+	}
+	if len(m.Closed) > 0 && m.Closed != when(issue.ClosedAt, env) {
+		return false
+	}
+	if len(m.Labels) > 0 && !lo.SomeBy(labels(ghi), func(label string) bool {
+		return lo.ContainsBy(m.Labels, func(name string) bool { return strings.EqualFold(name, label) })
+	}) {
+		return false
+	}
+	if len(m.Repos) > 0 && !lo.ContainsBy(m.Repos, func(repo string) bool { return strings.EqualFold(repo, ghi.Repository) }) {
+		return false
+	}
+	if len(m.Roles) > 0 && len(lo.Intersect(m.Roles, Roles(env.User, ghi))) == 0 {
+		return false
+	}
+	if m.Commented != nil && *m.Commented != (ghi.Comments > 0) {
+		return false
+	}
+	if m.Not != nil && m.Not.matchesAny(env, ghi) {
+		return false
+	}
+
+	return true
+}
+
+// matchesAny returns true if any of the conditions hold, for negating them.
+func (m *Match) matchesAny(env Env, ghi *format.GitHubIssue) bool {
+	conditions := []*Match{
+		{Type: m.Type},
+		{State: m.State},
+		{StateReason: m.StateReason},
+		{Merged: m.Merged},
+		{Created: m.Created},
+		{Closed: m.Closed},
+		{Labels: m.Labels},
+		{Repos: m.Repos},
+		{Roles: m.Roles},
+		{Commented: m.Commented},
+		{Not: m.Not},
+	}
+
+	return lo.SomeBy(conditions, func(c *Match) bool {
+		return !c.empty() && c.matches(env, ghi)
+	})
+}
+
+func (m *Match) empty() bool {
+	return len(m.Type) == 0 && len(m.State) == 0 && len(m.StateReason) == 0 && m.Merged == nil &&
+		len(m.Created) == 0 && len(m.Closed) == 0 && len(m.Labels) == 0 && len(m.Repos) == 0 &&
+		len(m.Roles) == 0 && m.Commented == nil && m.Not == nil
+}
+
+// Roles returns the roles that the user has on the item.
+func Roles(user string, ghi *format.GitHubIssue) []string {
+	var out []string
+	if strings.EqualFold(ghi.Issue.GetUser().GetLogin(), user) {
+		out = append(out, RoleAuthor)
+	}
+	if ghi.Review != nil {
+		out = append(out, RoleReviewer)
+	}
+	if lo.ContainsBy(ghi.Issue.Assignees, func(assignee *github.User) bool {
+		return strings.EqualFold(assignee.GetLogin(), user)
+	}) {
+		out = append(out, RoleAssignee)
+	}
+	if ghi.Comments > 0 {
+		out = append(out, RoleCommenter)
+	}
+
+	return out
+}
+
+// labels returns the names of the item's labels, from both the search results and
+// the pull request details.
+func labels(ghi *format.GitHubIssue) []string {
+	names := lo.Map(ghi.Issue.Labels, func(label *github.Label, _ int) string {
+		return label.GetName()
+	})
+
+	return lo.Uniq(append(names, ghi.Labels...))
+}
+
+func itemType(issue *github.Issue) string {
+	if issue.IsPullRequest() {
+		return format.ItemTypePullRequest
+	}
+
+	return format.ItemTypeIssue
+}
+
+// when describes when the time was relative to the window. Windows that end in the
+// past can still find items that were closed or merged after the window, since
+// the search only needs them to be created or updated within it.
+func when(t *github.Timestamp, env Env) string {
+	switch {
+	case t == nil || t.IsZero():
+		return WhenNever
+	case t.Before(env.Start):
+		return WhenBefore
+	case util.InTimeRange(t.Time, env.Start, env.End):
+		return WhenWithin
+	}
+
+	return WhenAfter
+}
@@ -0,0 +1,238 @@
+package rules_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/format"
+	"github.com/chrisyxlee/snippets/internal/rules"
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var env = rules.Env{
+	User:  "octocat",
+	Start: time.Date(2026, time.October, 5, 0, 0, 0, 0, time.UTC),
+	End:   time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+}
+
+func newIssue(number int, author string, state string, closedAt *time.Time) *format.GitHubIssue {
+	issue := &github.Issue{
+		Number:    github.Int(number),
+		State:     github.String(state),
+		User:      &github.User{Login: github.String(author)},
+		CreatedAt: &github.Timestamp{Time: env.Start.AddDate(0, 0, -30)},
+	}
+	if closedAt != nil {
+		issue.ClosedAt = &github.Timestamp{Time: *closedAt}
+	}
+
+	return &format.GitHubIssue{Issue: issue, Repository: "octo/widgets"}
+}
+
+func placed(placements []*rules.Placement) map[int]string {
+	out := map[int]string{}
+	for _, p := range placements {
+		out[p.Issue.Issue.GetNumber()] = p.Section + " by " + p.Rule
+	}
+	return out
+}
+
+// report creates a report with the sections, like the command does.
+func report(sections []*rules.Section) *format.Report {
+	r := format.NewReport(env.User, env.Start, env.End)
+	for _, section := range sections {
+		r.AddSection(section.Title, section.Issues)
+	}
+	return r
+}
+
+func TestNames(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"cycle", "end-goal"}, rules.Names())
+	assert.Contains(t, rules.Names(), rules.DefaultName)
+}
+
+func TestCycle(t *testing.T) {
+	t.Parallel()
+
+	closedWithin := env.Start.Add(time.Hour)
+	closedBefore := env.Start.Add(-time.Hour)
+
+	completed := newIssue(1, "octocat", "closed", &closedWithin)
+	// Open issues have no closed time, which must not count as closed before the
+	// window.
+	commented := newIssue(2, "octocat", "open", nil)
+	commented.Comments = 1
+	commentedAfterClose := newIssue(3, "octocat", "closed", &closedBefore)
+	commentedAfterClose.Comments = 2
+	remaining := newIssue(4, "octocat", "open", nil)
+	reviewed := newIssue(5, "hubot", "open", nil)
+	reviewed.Review = &format.Review{}
+	discussed := newIssue(6, "hubot", "open", nil)
+	discussed.Comments = 1
+
+	rs, err := rules.Load("cycle")
+	require.NoError(t, err)
+
+	sections, placements := rs.Classify(env, []*format.GitHubIssue{
+		completed, commented, commentedAfterClose, remaining, reviewed, discussed,
+	})
+
+	assert.Equal(t, []string{"Completed this cycle", "Updated this cycle", "Reviewed", "Discussed", "Remaining"},
+		lo.Map(sections, func(s *rules.Section, _ int) string { return s.Title }))
+	assert.Equal(t, map[int]string{
+		1: "Completed this cycle by closed-within-window",
+		2: "Updated this cycle by commented-within-window",
+		3: "Updated this cycle by commented-within-window",
+		4: "Remaining by ",
+		5: "Reviewed by reviewed",
+		6: "Discussed by discussed",
+	}, placed(placements))
+	assert.Equal(t, []*format.GitHubIssue{commented, commentedAfterClose}, sections[1].Issues)
+}
+
+func TestEndGoal(t *testing.T) {
+	t.Parallel()
+
+	mergedAt := env.Start.Add(48 * time.Hour)
+	newPull := func(number int, createdAt time.Time, merged bool) *format.GitHubIssue {
+		ghi := newIssue(number, "octocat", "open", nil)
+		ghi.Issue.PullRequestLinks = &github.PullRequestLinks{}
+		ghi.Issue.CreatedAt = &github.Timestamp{Time: createdAt}
+		if merged {
+			ghi.Issue.State = github.String("closed")
+			ghi.Issue.ClosedAt = &github.Timestamp{Time: mergedAt}
+			ghi.Merged = true
+		}
+		return ghi
+	}
+
+	createdAndMerged := newPull(1, env.Start.Add(time.Hour), true)
+	pushed := newPull(2, env.Start.AddDate(0, 0, -3), true)
+	pushedToo := newPull(3, env.Start.AddDate(0, -1, 0), true)
+	started := newPull(4, env.Start.Add(time.Hour), false)
+	filed := newIssue(5, "octocat", "open", nil)
+	filed.Issue.CreatedAt = &github.Timestamp{Time: env.Start.Add(time.Hour)}
+	updated := newIssue(6, "octocat", "closed", &mergedAt)
+	stale := newIssue(7, "octocat", "open", nil)
+	// Merged after a window that ended in the past, so it was open at the end.
+	mergedLater := newPull(8, env.Start.Add(time.Hour), true)
+	mergedLater.Issue.ClosedAt = &github.Timestamp{Time: env.End.Add(time.Hour)}
+
+	rs := rules.Default()
+	sections, placements := rs.Classify(env, []*format.GitHubIssue{
+		createdAndMerged, pushed, pushedToo, started, filed, updated, stale, mergedLater,
+	})
+	assert.Equal(t, map[int]string{
+		1: "Created and merged by created-and-merged",
+		2: "Pushed over the line by pushed-over-the-line",
+		3: "Pushed over the line by pushed-over-the-line",
+		4: "Started or continued by started-or-continued",
+		5: "Filed by filed",
+		6: "Updated by closed-issue",
+		7: "Remaining by ",
+		8: "Started or continued by merged-after-window",
+	}, placed(placements))
+
+	assert.Equal(t, []string{
+		"created and merged 1 pull request",
+		"pushed 2 pull requests over the line",
+		"started or continued work on 2 pull requests",
+		"filed 1 new issue",
+		"updated 1 issue",
+	}, rs.Summarize(report(sections).Sections))
+}
+
+func TestMultiMatch(t *testing.T) {
+	t.Parallel()
+
+	rs, err := rules.Parse([]byte(`policy: multi-match
+rules:
+  - section: Bugs
+    match:
+      labels: [Bug]
+  - name: mine
+    section: Mine
+    match:
+      roles: [author, assignee]
+      repos: [octo/widgets]
+`))
+	require.NoError(t, err)
+
+	bug := newIssue(1, "octocat", "open", nil)
+	bug.Issue.Labels = []*github.Label{{Name: github.String("bug")}}
+	assigned := newIssue(2, "hubot", "open", nil)
+	assigned.Issue.Assignees = []*github.User{{Login: github.String("OctoCat")}}
+	other := newIssue(3, "hubot", "open", nil)
+
+	sections, placements := rs.Classify(env, []*format.GitHubIssue{bug, assigned, other})
+	require.Len(t, sections, 2)
+	assert.Equal(t, []*format.GitHubIssue{bug}, sections[0].Issues)
+	assert.Equal(t, []*format.GitHubIssue{bug, assigned}, sections[1].Issues)
+	assert.Equal(t, []string{"Bugs", "mine", "mine"}, lo.Map(placements, func(p *rules.Placement, _ int) string {
+		return p.Rule
+	}))
+}
+
+func TestConditions(t *testing.T) {
+	t.Parallel()
+
+	mergedAt := env.Start.Add(time.Hour)
+	merged := newIssue(1, "octocat", "closed", &mergedAt)
+	merged.Merged = true
+	merged.Issue.PullRequestLinks = &github.PullRequestLinks{}
+	merged.Issue.CreatedAt = &github.Timestamp{Time: env.Start}
+	notPlanned := newIssue(2, "octocat", "closed", &mergedAt)
+	notPlanned.Issue.StateReason = github.String("not_planned")
+	// The search finds items updated within the window, which can be closed after
+	// it.
+	closedAt := env.End.Add(time.Hour)
+	closedAfter := newIssue(3, "octocat", "closed", &closedAt)
+	// Pull request labels can come from GraphQL instead of the search results.
+	labeled := newIssue(4, "octocat", "open", nil)
+	labeled.Labels = []string{"feature"}
+
+	for _, tc := range []struct {
+		rules string
+		want  []int
+	}{
+		{rules: "type: pull_request\n      merged: true", want: []int{1}},
+		{rules: "created: within", want: []int{1}},
+		{rules: "created: before\n      closed: within", want: []int{2}},
+		{rules: "closed: after", want: []int{3}},
+		{rules: "state_reason: not_planned", want: []int{2}},
+		{rules: "not:\n        state_reason: not_planned", want: []int{1, 3, 4}},
+		{rules: "closed: never", want: []int{4}},
+		{rules: "labels: [Feature]", want: []int{4}},
+		{rules: "repos: [octo/gadgets]", want: []int{}},
+	} {
+		rs, err := rules.Parse([]byte("rules:\n  - section: Matched\n    match:\n      " + tc.rules + "\n"))
+		require.NoError(t, err, tc.rules)
+
+		sections, _ := rs.Classify(env, []*format.GitHubIssue{merged, notPlanned, closedAfter, labeled})
+		assert.Equal(t, tc.want, lo.Map(sections[0].Issues, func(ghi *format.GitHubIssue, _ int) int {
+			return ghi.Issue.GetNumber()
+		}), tc.rules)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	t.Parallel()
+
+	for data, want := range map[string]string{
+		"rules: []":                                                             "no rules",
+		"policy: first\nrules:\n  - section: A":                                 "unknown policy `first`",
+		"rules:\n  - match:\n      state: open":                                 "rules[0]: no section",
+		"rules:\n  - section: A\n    match:\n      roles: [owner]":              "rules[0] (A): unknown role `owner`",
+		"rules:\n  - section: A\n    match:\n      repos: [widgets]":            "repo `widgets` must be formatted as owner/name",
+		"rules:\n  - section: A\n    match:\n      not:\n        closed: later": "not: unknown closed `later`",
+		"rules:\n  - section: A\n    match:\n      label: [bug]":                "field label not found",
+	} {
+		_, err := rules.Parse([]byte(data))
+		assert.ErrorContains(t, err, want, data)
+	}
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal"
+	"github.com/chrisyxlee/snippets/internal/page"
+	"github.com/google/go-github/v53/github"
+)
+
+const (
+	// GitHub search only returns the first 1000 results for any query.
+	MaxResults = 1000
+
+	perPage = 100
+
+	// Ranges shorter than this aren't split any further, even if the results are
+	// truncated.
+	minSplit = time.Minute
+
+	timeLayout = "2006-01-02T15:04:05Z"
+)
+
+// IssueSearcher searches issues and pull requests. It is satisfied by
+// github.SearchService.
+type IssueSearcher interface {
+	Issues(ctx context.Context, query string, opts *github.SearchOptions) (*github.IssuesSearchResult, *github.Response, error)
+}
+
+// Issues searches for issues and pull requests matching the query whose date
+// field (i.e. "created" or "updated") falls within [start, end). Every page of
+// results is read. Because GitHub search stops at 1000 results, the range is
+// split in half and each half is searched separately whenever the results would
+// be truncated.
+func Issues(ctx context.Context, searcher IssueSearcher, query string, field string, start time.Time, end time.Time) ([]*github.Issue, error) {
+	q := Qualify(query, field, start, end)
+	internal.Log().Debug().Str("query", q).Msg("search issues")
+
+	var (
+		issues    []*github.Issue
+		truncated bool
+	)
+	err := page.Paginate(q, perPage, func(listOptions github.ListOptions) (page.Details, *github.Response, error) {
+		res, resp, err := searcher.Issues(ctx, q, &github.SearchOptions{
+			Sort:        "updated",
+			Order:       "asc",
+			ListOptions: listOptions,
+		})
+		if err != nil {
+			return page.Details{}, resp, err
+		}
+
+		if res.GetTotal() > MaxResults || res.GetIncompleteResults() {
+			if listOptions.Page == 1 && end.Sub(start) > minSplit {
+				truncated = true
+				return page.Details{StopEarly: true}, resp, nil
+			}
+
+			internal.Log().Warn().
+				Str("query", q).
+				Int("total", res.GetTotal()).
+				Bool("incomplete", res.GetIncompleteResults()).
+				Msg("search results are truncated")
+		}
+
+		issues = append(issues, res.Issues...)
+		return page.Details{}, resp, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search issues with query `%s`: %w", q, err)
+	}
+
+	if !truncated {
+		return issues, nil
+	}
+
+	mid := start.Add(end.Sub(start) / 2).Truncate(time.Second)
+	internal.Log().Debug().
+		Str("query", q).
+		Time("split", mid).
+		Msg("splitting truncated search")
+
+	before, err := Issues(ctx, searcher, query, field, start, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := Issues(ctx, searcher, query, field, mid, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(before, after...), nil
+}
+
+// ScopeQualifiers limits a search to the repositories and organizations, minus the
+// excluded repositories. GitHub treats multiple repo and org qualifiers as OR.
+func ScopeQualifiers(repos []string, orgs []string, excludeRepos []string) (string, error) {
+	var qualifiers []string
+	for _, repo := range append(append([]string{}, repos...), excludeRepos...) {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || len(owner) == 0 || len(name) == 0 || strings.Contains(name, "/") {
+			return "", fmt.Errorf("repository `%s` must be formatted as owner/name", repo)
+		}
+	}
+
+	for _, repo := range repos {
+		qualifiers = append(qualifiers, "repo:"+repo)
+	}
+	for _, org := range orgs {
+		qualifiers = append(qualifiers, "org:"+org)
+	}
+	for _, repo := range excludeRepos {
+		qualifiers = append(qualifiers, "-repo:"+repo)
+	}
+
+	return strings.Join(qualifiers, " "), nil
+}
+
+// Qualify adds a qualifier to the query that matches the date field within
+// [start, end). GitHub date ranges are inclusive, so the range ends one second
+// before end.
+func Qualify(query string, field string, start time.Time, end time.Time) string {
+	return fmt.Sprintf("%s %s:%s..%s",
+		query,
+		field,
+		start.UTC().Format(timeLayout),
+		end.Add(-time.Second).UTC().Format(timeLayout))
+}
@@ -0,0 +1,152 @@
+package search_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/search"
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var reRange = regexp.MustCompile(`created:(\S+)\.\.(\S+)`)
+
+// fakeSearcher returns the issues created within the queried range, truncating
+// the results the same way GitHub does.
+type fakeSearcher struct {
+	issues   []*github.Issue
+	queries  []string
+	rateLeft int
+}
+
+func (fs *fakeSearcher) Issues(_ context.Context, query string, opts *github.SearchOptions) (*github.IssuesSearchResult, *github.Response, error) {
+	fs.queries = append(fs.queries, query)
+
+	if fs.rateLeft > 0 {
+		fs.rateLeft--
+		return nil, nil, &github.RateLimitError{
+			Rate: github.Rate{
+				Reset: github.Timestamp{Time: time.Now().Add(-time.Minute)},
+			},
+		}
+	}
+
+	group := reRange.FindStringSubmatch(query)
+	if len(group) < 3 {
+		return nil, nil, fmt.Errorf("no range in query `%s`", query)
+	}
+	start, err := time.Parse(time.RFC3339, group[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := time.Parse(time.RFC3339, group[2])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matched := lo.Filter(fs.issues, func(issue *github.Issue, _ int) bool {
+		created := issue.GetCreatedAt().Time
+		return !created.Before(start) && !created.After(end)
+	})
+	total := len(matched)
+	if len(matched) > search.MaxResults {
+		matched = matched[:search.MaxResults]
+	}
+
+	from := (opts.Page - 1) * opts.PerPage
+	to := lo.Min([]int{from + opts.PerPage, len(matched)})
+	resp := &github.Response{}
+	if to < len(matched) {
+		resp.NextPage = opts.Page + 1
+		resp.LastPage = (len(matched) + opts.PerPage - 1) / opts.PerPage
+	}
+
+	return &github.IssuesSearchResult{
+		Total:             github.Int(total),
+		IncompleteResults: github.Bool(false),
+		Issues:            matched[from:to],
+	}, resp, nil
+}
+
+func makeIssues(count int, start time.Time, step time.Duration) []*github.Issue {
+	issues := make([]*github.Issue, 0, count)
+	for i := 0; i < count; i++ {
+		issues = append(issues, &github.Issue{
+			Number:    github.Int(i),
+			CreatedAt: &github.Timestamp{Time: start.Add(time.Duration(i) * step)},
+		})
+	}
+	return issues
+}
+
+func TestIssuesAllPages(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(14 * 24 * time.Hour)
+	fs := &fakeSearcher{issues: makeIssues(250, start, time.Hour)}
+
+	issues, err := search.Issues(context.Background(), fs, "author:me", "created", start, end)
+	require.NoError(t, err)
+	assert.Len(t, issues, 250)
+	assert.Len(t, fs.queries, 3)
+}
+
+func TestIssuesSplitTruncatedRange(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(14 * 24 * time.Hour)
+	fs := &fakeSearcher{issues: makeIssues(2500, start, 5*time.Minute)}
+
+	issues, err := search.Issues(context.Background(), fs, "author:me", "created", start, end)
+	require.NoError(t, err)
+	assert.Len(t, issues, 2500)
+	assert.Len(t, lo.UniqBy(issues, func(issue *github.Issue) int {
+		return issue.GetNumber()
+	}), 2500)
+}
+
+func TestIssuesRateLimited(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	fs := &fakeSearcher{issues: makeIssues(10, start, time.Hour), rateLeft: 1}
+
+	issues, err := search.Issues(context.Background(), fs, "author:me", "created", start, end)
+	require.NoError(t, err)
+	assert.Len(t, issues, 10)
+	assert.Len(t, fs.queries, 2)
+}
+
+func TestQualify(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t,
+		"author:me updated:2026-10-01T00:00:00Z..2026-10-14T23:59:59Z",
+		search.Qualify("author:me", "updated", start, start.AddDate(0, 0, 14)))
+}
+
+func TestScopeQualifiers(t *testing.T) {
+	t.Parallel()
+
+	got, err := search.ScopeQualifiers([]string{"octo/widgets", "octo/gears"}, []string{"acme"}, []string{"acme/legacy"})
+	require.NoError(t, err)
+	assert.Equal(t, "repo:octo/widgets repo:octo/gears org:acme -repo:acme/legacy", got)
+
+	got, err = search.ScopeQualifiers(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = search.ScopeQualifiers([]string{"widgets"}, nil, nil)
+	assert.Error(t, err)
+	_, err = search.ScopeQualifiers(nil, nil, []string{"octo/widgets/extra"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,308 @@
+// Package slack reads messages from a Slack workspace export, which has a
+// directory for each channel with a JSON file of messages for each day.
+package slack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/util"
+)
+
+// Message is a message that the user sent.
+type Message struct {
+	ChannelID string
+	// Name of the channel, without the leading "#".
+	Channel string
+	// Text of the message with Slack's markup for links and mentions replaced by
+	// their labels.
+	Text string
+	// Timestamp of the message, which is also its ID within the channel.
+	TS     string
+	SentAt time.Time
+	// Timestamp of the message that started the thread, if the message is part of
+	// a thread.
+	ThreadTS string
+	// Number of replies to the message, if it started a thread.
+	Replies int
+	// Counts of each reaction, keyed by the GitHub reaction name that it's
+	// closest to. Reactions without a close GitHub reaction are left out.
+	Reactions map[string]int
+}
+
+// Permalink returns the link to the message in the workspace, i.e.
+// "https://octo.slack.com/archives/C012AB3CD/p1760788800000100".
+func (m *Message) Permalink(workspaceURL string) string {
+	link := fmt.Sprintf("%s/archives/%s/p%s", strings.TrimSuffix(workspaceURL, "/"), m.ChannelID,
+		strings.ReplaceAll(m.TS, ".", ""))
+	if len(m.ThreadTS) > 0 && m.ThreadTS != m.TS {
+		link += fmt.Sprintf("?thread_ts=%s&cid=%s", m.ThreadTS, m.ChannelID)
+	}
+
+	return link
+}
+
+// Export is an opened Slack workspace export.
+type Export struct {
+	fsys   fs.FS
+	closer io.Closer
+}
+
+// Open opens the export at the path, which is either the zip file that Slack
+// exports to or the directory that it was extracted to.
+func Open(p string) (*Export, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &Export{fsys: os.DirFS(p)}, nil
+	}
+
+	r, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, fmt.Errorf("open slack export %s: %w", p, err)
+	}
+
+	return &Export{fsys: r, closer: r}, nil
+}
+
+// Close closes the export.
+func (e *Export) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+
+	return e.closer.Close()
+}
+
+type channel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type message struct {
+	Type       string `json:"type"`
+	Subtype    string `json:"subtype"`
+	User       string `json:"user"`
+	Text       string `json:"text"`
+	TS         string `json:"ts"`
+	ThreadTS   string `json:"thread_ts"`
+	ReplyCount int    `json:"reply_count"`
+	Reactions  []struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	} `json:"reactions"`
+}
+
+// Subtypes of messages that the user wrote themselves, as opposed to joining a
+// channel and the like.
+var userSubtypes = map[string]bool{
+	"":                 true,
+	"thread_broadcast": true,
+	"me_message":       true,
+	"file_share":       true,
+}
+
+// Messages returns the messages that the user sent within [start, end), oldest
+// first. The user is matched by their ID or their username.
+func (e *Export) Messages(userIDOrName string, start time.Time, end time.Time) ([]*Message, error) {
+	users, err := e.users()
+	if err != nil {
+		return nil, err
+	}
+
+	userID := userIDOrName
+	for _, u := range users {
+		if strings.EqualFold(u.Name, userIDOrName) {
+			userID = u.ID
+		}
+	}
+
+	// Public channels are in channels.json, and private channels in groups.json.
+	var channels []channel
+	for _, name := range []string{"channels.json", "groups.json"} {
+		var c []channel
+		if err = e.readJSON(name, &c); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		channels = append(channels, c...)
+	}
+	if len(channels) == 0 {
+		return nil, errors.New("slack export has no channels.json or groups.json")
+	}
+
+	var messages []*Message
+	for _, c := range channels {
+		days, err := fs.ReadDir(e.fsys, c.Name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read slack channel %s: %w", c.Name, err)
+		}
+
+		for _, day := range days {
+			// Days are named after their date in the workspace's time zone, so they
+			// can be a day off from the window.
+			date, err := time.Parse("2006-01-02.json", day.Name())
+			if err != nil || date.Before(start.AddDate(0, 0, -1)) || !date.Before(end.AddDate(0, 0, 1)) {
+				continue
+			}
+
+			var raw []*message
+			if err = e.readJSON(path.Join(c.Name, day.Name()), &raw); err != nil {
+				return nil, err
+			}
+
+			for _, m := range raw {
+				if m.Type != "message" || m.User != userID || !userSubtypes[m.Subtype] {
+					continue
+				}
+
+				sentAt, err := parseTS(m.TS)
+				if err != nil {
+					return nil, fmt.Errorf("slack message in %s: %w", path.Join(c.Name, day.Name()), err)
+				}
+				if !util.InTimeRange(sentAt, start, end) {
+					continue
+				}
+
+				reactions := make(map[string]int)
+				for _, r := range m.Reactions {
+					if name, ok := reactionNames[r.Name]; ok {
+						reactions[name] += r.Count
+					}
+				}
+
+				messages = append(messages, &Message{
+					ChannelID: c.ID,
+					Channel:   c.Name,
+					Text:      clean(m.Text, users),
+					TS:        m.TS,
+					SentAt:    sentAt,
+					ThreadTS:  m.ThreadTS,
+					Replies:   m.ReplyCount,
+					Reactions: reactions,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].SentAt.Before(messages[j].SentAt)
+	})
+	return messages, nil
+}
+
+// users returns the users in the export, keyed by their ID. Exports don't always
+// include them, in which case mentions are left as IDs.
+func (e *Export) users() (map[string]*user, error) {
+	var list []*user
+	if err := e.readJSON("users.json", &list); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	users := make(map[string]*user, len(list))
+	for _, u := range list {
+		users[u.ID] = u
+	}
+	return users, nil
+}
+
+func (e *Export) readJSON(name string, v any) error {
+	data, err := fs.ReadFile(e.fsys, name)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse slack export %s: %w", name, err)
+	}
+	return nil
+}
+
+// parseTS parses a message timestamp, i.e. "1760788800.000100", which is the Unix
+// time in seconds followed by microseconds.
+func parseTS(ts string) (time.Time, error) {
+	secs, micros, _ := strings.Cut(ts, ".")
+	s, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp `%s`", ts)
+	}
+
+	var us int64
+	if len(micros) > 0 {
+		if us, err = strconv.ParseInt(micros, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp `%s`", ts)
+		}
+	}
+
+	return time.Unix(s, us*int64(time.Microsecond)).UTC(), nil
+}
+
+// Slack reactions keyed by their name, mapped to the closest GitHub reaction.
+var reactionNames = map[string]string{
+	"+1":            "+1",
+	"thumbsup":      "+1",
+	"-1":            "-1",
+	"thumbsdown":    "-1",
+	"heart":         "heart",
+	"eyes":          "eyes",
+	"rocket":        "rocket",
+	"tada":          "hooray",
+	"laughing":      "laugh",
+	"smile":         "laugh",
+	"joy":           "laugh",
+	"confused":      "confused",
+	"thinking_face": "confused",
+}
+
+// markup matches Slack's markup for links and mentions, i.e. "<@U012AB3CD>",
+// "<#C012AB3CD|general>", or "<https://example.com|example>".
+var markup = regexp.MustCompile(`<([^<>|]+)(?:\|([^<>]*))?>`)
+
+// clean replaces the markup in the text with its labels and unescapes it.
+func clean(text string, users map[string]*user) string {
+	text = markup.ReplaceAllStringFunc(text, func(s string) string {
+		m := markup.FindStringSubmatch(s)
+		target, label := m[1], m[2]
+		switch {
+		case strings.HasPrefix(target, "@"):
+			if u, ok := users[target[1:]]; ok {
+				return "@" + u.Name
+			}
+			return target
+		case strings.HasPrefix(target, "#"):
+			if len(label) > 0 {
+				return "#" + label
+			}
+			return target
+		case strings.HasPrefix(target, "!"):
+			// Special mentions, i.e. "<!here>".
+			return "@" + strings.TrimPrefix(target, "!")
+		case len(label) > 0:
+			return label
+		}
+		return target
+	})
+
+	return strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&").Replace(text)
+}
@@ -0,0 +1,105 @@
+package slack_test
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/slack"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	start = time.Date(2026, time.October, 4, 12, 0, 0, 0, time.UTC)
+	end   = start.AddDate(0, 0, 14)
+)
+
+// zipExport zips the export like Slack does.
+func zipExport(t *testing.T, dir string) string {
+	path := filepath.Join(t.TempDir(), "export.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	require.NoError(t, filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		fw, err := w.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(data)
+		return err
+	}))
+	require.NoError(t, w.Close())
+
+	return path
+}
+
+func TestMessages(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("testdata", "export")
+	for name, path := range map[string]string{
+		"directory": dir,
+		"zip":       zipExport(t, dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			export, err := slack.Open(path)
+			require.NoError(t, err)
+			t.Cleanup(func() { assert.NoError(t, export.Close()) })
+
+			for _, user := range []string{"U01OCTOCAT", "octocat"} {
+				messages, err := export.Messages(user, start, end)
+				require.NoError(t, err)
+				require.Equal(t, []string{
+					"Sprockets are out, thanks @hubot! See #12 & #widgets",
+					"Thanks!",
+					"Lunch?",
+				}, lo.Map(messages, func(m *slack.Message, _ int) string { return m.Text }))
+
+				announcement := messages[0]
+				assert.Equal(t, "general", announcement.Channel)
+				assert.Equal(t, 3, announcement.Replies)
+				assert.Equal(t, map[string]int{"hooray": 4, "+1": 2}, announcement.Reactions)
+				assert.Equal(t, time.Date(2026, time.October, 10, 15, 0, 0, 100000, time.UTC), announcement.SentAt)
+				assert.Equal(t, "https://octo.slack.com/archives/C01GENERAL/p1791644400000100",
+					announcement.Permalink("https://octo.slack.com/"))
+
+				assert.Equal(t, "https://octo.slack.com/archives/C01GENERAL/p1791646200000100?thread_ts=1791644400.000100&cid=C01GENERAL",
+					messages[1].Permalink("https://octo.slack.com"))
+				assert.Equal(t, "random", messages[2].Channel)
+			}
+		})
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := slack.Open(filepath.Join("testdata", "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = slack.Open(filepath.Join("testdata", "export", "users.json"))
+	assert.ErrorContains(t, err, "open slack export")
+
+	export, err := slack.Open(t.TempDir())
+	require.NoError(t, err)
+	_, err = export.Messages("octocat", start, end)
+	assert.ErrorContains(t, err, "no channels.json")
+}
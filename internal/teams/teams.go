@@ -0,0 +1,188 @@
+// Package teams resolves everyone on a GitHub team, including the members of its
+// child teams, and caches the roster between reports.
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/output"
+	"github.com/chrisyxlee/snippets/internal/page"
+	"github.com/google/go-github/v53/github"
+	"github.com/samber/lo"
+)
+
+// Member is a member of the team or one of its child teams.
+type Member struct {
+	Login string `json:"login"`
+	// Whether the account is a GitHub App's bot rather than a person.
+	Bot bool `json:"bot,omitempty"`
+}
+
+// Roster is everyone on the team when it was resolved.
+type Roster struct {
+	// Team formatted as org/team-slug.
+	Team string `json:"team"`
+	// Slugs of the team and its child teams, however deeply they're nested.
+	Teams []string `json:"teams"`
+	// Members sorted by login.
+	Members    []Member  `json:"members"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// Parse splits the team, formatted as org/team-slug, into the organization and the
+// team's slug.
+func Parse(team string) (string, string, error) {
+	org, slug, ok := strings.Cut(team, "/")
+	if !ok || len(org) == 0 || len(slug) == 0 || strings.Contains(slug, "/") {
+		return "", "", fmt.Errorf("team `%s` must be formatted as org/team-slug", team)
+	}
+
+	return org, slug, nil
+}
+
+// Resolve lists the members of the team, formatted as org/team-slug, and of its
+// child teams. Child teams are walked as well, rather than relying on the team's
+// member list to include them.
+func Resolve(ctx context.Context, client *github.Client, team string, now time.Time) (*Roster, error) {
+	org, slug, err := Parse(team)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Roster{Team: team, ResolvedAt: now}
+	members := make(map[string]Member)
+	seen := map[string]bool{slug: true}
+	for queue := []string{slug}; len(queue) > 0; queue = queue[1:] {
+		current := queue[0]
+		r.Teams = append(r.Teams, current)
+
+		err = page.Paginate(fmt.Sprintf("members of %s/%s", org, current), 100,
+			func(opts github.ListOptions) (page.Details, *github.Response, error) {
+				res, resp, err := client.Teams.ListTeamMembersBySlug(ctx, org, current, &github.TeamListTeamMembersOptions{
+					ListOptions: opts,
+				})
+				if err != nil {
+					return page.Details{}, resp, err
+				}
+
+				for _, user := range res {
+					members[strings.ToLower(user.GetLogin())] = Member{
+						Login: user.GetLogin(),
+						Bot:   user.GetType() == "Bot" || strings.HasSuffix(user.GetLogin(), "[bot]"),
+					}
+				}
+				return page.Details{}, resp, nil
+			})
+		if err != nil {
+			return nil, fmt.Errorf("list members of %s/%s: %w", org, current, err)
+		}
+
+		err = page.Paginate(fmt.Sprintf("child teams of %s/%s", org, current), 100,
+			func(opts github.ListOptions) (page.Details, *github.Response, error) {
+				res, resp, err := client.Teams.ListChildTeamsByParentSlug(ctx, org, current, &opts)
+				if err != nil {
+					return page.Details{}, resp, err
+				}
+
+				for _, child := range res {
+					if !seen[child.GetSlug()] {
+						seen[child.GetSlug()] = true
+						queue = append(queue, child.GetSlug())
+					}
+				}
+				return page.Details{}, resp, nil
+			})
+		if err != nil {
+			return nil, fmt.Errorf("list child teams of %s/%s: %w", org, current, err)
+		}
+	}
+
+	r.Members = lo.Values(members)
+	sort.Slice(r.Members, func(i, j int) bool {
+		return strings.ToLower(r.Members[i].Login) < strings.ToLower(r.Members[j].Login)
+	})
+	return r, nil
+}
+
+// ValidateExclude checks that the exclude patterns are valid path.Match patterns.
+func ValidateExclude(exclude []string) error {
+	for _, pattern := range exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern `%s`: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Logins returns the logins of the members who are people, leaving out bots and
+// the logins that match any of the exclude patterns, i.e. "deploy-*" for service
+// accounts. Patterns match case-insensitively.
+func (r *Roster) Logins(exclude []string) []string {
+	var logins []string
+	for _, m := range r.Members {
+		if m.Bot {
+			continue
+		}
+
+		excluded := lo.ContainsBy(exclude, func(pattern string) bool {
+			ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(m.Login))
+			return ok
+		})
+		if !excluded {
+			logins = append(logins, m.Login)
+		}
+	}
+
+	return logins
+}
+
+// Path is the file that the team's roster is cached in, with the team formatted as
+// org/team-slug.
+func Path(dir string, team string) string {
+	org, slug, _ := strings.Cut(team, "/")
+	return filepath.Join(dir, "teams", org, slug+".json")
+}
+
+// Load reads the roster cached at the path. No roster is returned if nothing was
+// cached yet.
+func Load(path string) (*Roster, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var r Roster
+	if err = json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse team roster %s: %w", path, err)
+	}
+
+	return &r, nil
+}
+
+// Save caches the roster at the path.
+func (r *Roster) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create team roster directory: %w", err)
+	}
+
+	return output.WriteFile(path, append(data, '\n'), true)
+}
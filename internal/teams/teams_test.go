@@ -0,0 +1,88 @@
+package teams_test
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/fakegithub"
+	"github.com/chrisyxlee/snippets/internal/teams"
+	"github.com/google/go-github/v53/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	s := fakegithub.New("octocat")
+	t.Cleanup(s.Close)
+	s.AddTeams(
+		&fakegithub.Team{Org: "octo", Slug: "eng", Members: []string{"octocat", "dependabot[bot]"}},
+		&fakegithub.Team{Org: "octo", Slug: "widgets", Parent: "eng", Members: []string{"hubot", "octocat"}},
+		&fakegithub.Team{Org: "octo", Slug: "sprockets", Parent: "widgets", Members: []string{"Monalisa", "deploy-bot"}},
+		&fakegithub.Team{Org: "octo", Slug: "design", Members: []string{"mona"}},
+	)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(s.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+
+	r, err := teams.Resolve(context.Background(), client, "octo/eng", now)
+	require.NoError(t, err)
+	assert.Equal(t, "octo/eng", r.Team)
+	assert.Equal(t, []string{"eng", "widgets", "sprockets"}, r.Teams)
+	assert.Equal(t, []teams.Member{
+		{Login: "dependabot[bot]", Bot: true},
+		{Login: "deploy-bot"},
+		{Login: "hubot"},
+		{Login: "Monalisa"},
+		{Login: "octocat"},
+	}, r.Members)
+	assert.Equal(t, now, r.ResolvedAt)
+
+	assert.Equal(t, []string{"deploy-bot", "hubot", "Monalisa", "octocat"}, r.Logins(nil))
+	assert.Equal(t, []string{"hubot", "octocat"}, r.Logins([]string{"deploy-*", "monalisa"}))
+
+	_, err = teams.Resolve(context.Background(), client, "octo/missing", now)
+	assert.Error(t, err)
+
+	_, err = teams.Resolve(context.Background(), client, "octo", now)
+	assert.EqualError(t, err, "team `octo` must be formatted as org/team-slug")
+}
+
+func TestValidateExclude(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, teams.ValidateExclude([]string{"deploy-*", "svc-?"}))
+	assert.Error(t, teams.ValidateExclude([]string{"deploy-["}))
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := teams.Path(dir, "octo/eng")
+	assert.Equal(t, filepath.Join(dir, "teams", "octo", "eng.json"), path)
+
+	r, err := teams.Load(path)
+	require.NoError(t, err)
+	assert.Nil(t, r)
+
+	saved := &teams.Roster{
+		Team:       "octo/eng",
+		Teams:      []string{"eng"},
+		Members:    []teams.Member{{Login: "octocat"}},
+		ResolvedAt: now,
+	}
+	require.NoError(t, saved.Save(path))
+
+	r, err = teams.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, saved, r)
+}
@@ -0,0 +1,229 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period is a named length of time that a report can cover.
+type Period string
+
+const (
+	PeriodDay     Period = "day"
+	PeriodWeek    Period = "week"
+	PeriodBiweek  Period = "biweek"
+	PeriodMonth   Period = "month"
+	PeriodQuarter Period = "quarter"
+	PeriodYear    Period = "year"
+)
+
+const (
+	ISODateLayout = "2006-01-02"
+)
+
+var (
+	periods = []Period{PeriodDay, PeriodWeek, PeriodBiweek, PeriodMonth, PeriodQuarter, PeriodYear}
+
+	// Biweeks are counted from a fixed Monday so that the boundaries are stable
+	// across years.
+	biweekEpoch = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.Local)
+
+	reRelativeTime = regexp.MustCompile(`^(\d+)(d|w|mo|q|y)$`)
+)
+
+// ParsePeriod parses the name of a period, i.e. "week" or "month".
+func ParsePeriod(s string) (Period, error) {
+	for _, p := range periods {
+		if strings.EqualFold(s, string(p)) {
+			return p, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown period `%s`, expected one of %s", s, strings.Join(PeriodNames(), ", "))
+}
+
+// PeriodNames lists the names of all known periods from shortest to longest.
+func PeriodNames() []string {
+	names := make([]string, 0, len(periods))
+	for _, p := range periods {
+		names = append(names, string(p))
+	}
+	return names
+}
+
+// Adj describes how often a report covering the period is written, i.e. "weekly".
+func (p Period) Adj() string {
+	switch p {
+	case PeriodDay:
+		return "daily"
+	case PeriodWeek:
+		return "weekly"
+	case PeriodBiweek:
+		return "biweekly"
+	case PeriodMonth:
+		return "monthly"
+	case PeriodQuarter:
+		return "quarterly"
+	case PeriodYear:
+		return "yearly"
+	}
+
+	Assert(false, "unknown period `%s`", p)
+	return ""
+}
+
+// AddTo moves t by n periods. A negative n moves t backwards.
+func (p Period) AddTo(t time.Time, n int) time.Time {
+	switch p {
+	case PeriodDay:
+		return t.AddDate(0, 0, n)
+	case PeriodWeek:
+		return t.AddDate(0, 0, 7*n)
+	case PeriodBiweek:
+		return t.AddDate(0, 0, 14*n)
+	case PeriodMonth:
+		return t.AddDate(0, n, 0)
+	case PeriodQuarter:
+		return t.AddDate(0, 3*n, 0)
+	case PeriodYear:
+		return t.AddDate(n, 0, 0)
+	}
+
+	Assert(false, "unknown period `%s`", p)
+	return t
+}
+
+// Truncate returns the start of the calendar period containing t in the local
+// timezone. Weeks start on Monday, and biweeks are aligned to a fixed Monday.
+func (p Period) Truncate(t time.Time) time.Time {
+	t = t.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+
+	switch p {
+	case PeriodDay:
+		return day
+	case PeriodWeek:
+		return day.AddDate(0, 0, -daysSinceMonday(day))
+	case PeriodBiweek:
+		monday := day.AddDate(0, 0, -daysSinceMonday(day))
+		if weeksBetween(biweekEpoch, monday)%2 != 0 {
+			monday = monday.AddDate(0, 0, -7)
+		}
+		return monday
+	case PeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+	case PeriodQuarter:
+		month := t.Month() - (t.Month()-1)%3
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.Local)
+	case PeriodYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.Local)
+	}
+
+	Assert(false, "unknown period `%s`", p)
+	return t
+}
+
+// ParseRelativeWindow parses a calendar-aligned window relative to now, such as
+// "today", "yesterday", "this-month", or "last-week", into its period and range.
+// Windows for the current period end at now, while windows for the previous
+// period end where the current period starts.
+func ParseRelativeWindow(spec string, now time.Time) (Period, time.Time, time.Time, error) {
+	switch strings.ToLower(spec) {
+	case "today":
+		spec = "this-day"
+	case "yesterday":
+		spec = "last-day"
+	}
+
+	which, name, ok := strings.Cut(spec, "-")
+	if !ok {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("unknown relative window `%s`", spec)
+	}
+
+	p, err := ParsePeriod(name)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("relative window `%s`: %w", spec, err)
+	}
+
+	start := p.Truncate(now)
+	switch strings.ToLower(which) {
+	case "this":
+		return p, start, now, nil
+	case "last":
+		return p, p.AddTo(start, -1), start, nil
+	}
+
+	return "", time.Time{}, time.Time{}, fmt.Errorf("unknown relative window `%s`, expected this-<period> or last-<period>", spec)
+}
+
+// ParseRelativeTime parses a time relative to now, i.e. "3d" for three days ago.
+// Supported units are d (days), w (weeks), mo (months), q (quarters), and y (years).
+func ParseRelativeTime(spec string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(spec) {
+	case "now":
+		return now, nil
+	case "today":
+		return PeriodDay.Truncate(now), nil
+	case "yesterday":
+		return PeriodDay.AddTo(PeriodDay.Truncate(now), -1), nil
+	}
+
+	group := reRelativeTime.FindStringSubmatch(strings.ToLower(spec))
+	if len(group) < 3 {
+		return time.Time{}, fmt.Errorf("unknown relative time `%s`", spec)
+	}
+
+	n, err := strconv.Atoi(group[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("relative time `%s`: %w", spec, err)
+	}
+
+	var p Period
+	switch group[2] {
+	case "d":
+		p = PeriodDay
+	case "w":
+		p = PeriodWeek
+	case "mo":
+		p = PeriodMonth
+	case "q":
+		p = PeriodQuarter
+	case "y":
+		p = PeriodYear
+	}
+
+	return p.AddTo(now, -n), nil
+}
+
+// ParseTime parses a time in the format defined by TimeLayout, a date in the
+// format defined by ISODateLayout, or a time relative to now as accepted by
+// ParseRelativeTime.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if t, err := ParseLocalTime(s); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation(ISODateLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+
+	t, err := ParseRelativeTime(s, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse time `%s`, wanted layout `%s`, `%s`, or a relative time like `2w`", s, TimeLayout, ISODateLayout)
+	}
+
+	return t, nil
+}
+
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+func weeksBetween(from time.Time, to time.Time) int {
+	// Round to account for daylight savings transitions.
+	return int(math.Round(to.Sub(from).Hours()/24)) / 7
+}
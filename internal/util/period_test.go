@@ -0,0 +1,102 @@
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrisyxlee/snippets/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeriod(t *testing.T) {
+	t.Parallel()
+
+	p, err := util.ParsePeriod("Month")
+	assert.NoError(t, err)
+	assert.Equal(t, util.PeriodMonth, p)
+
+	_, err = util.ParsePeriod("fortnight")
+	assert.Error(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	// A Wednesday.
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.Local)
+
+	assert.Equal(t, time.Date(2026, time.October, 14, 0, 0, 0, 0, time.Local), util.PeriodDay.Truncate(now))
+	assert.Equal(t, time.Date(2026, time.October, 12, 0, 0, 0, 0, time.Local), util.PeriodWeek.Truncate(now))
+	assert.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local), util.PeriodMonth.Truncate(now))
+	assert.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local), util.PeriodQuarter.Truncate(now))
+	assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.Local), util.PeriodYear.Truncate(now))
+
+	biweek := util.PeriodBiweek.Truncate(now)
+	assert.Equal(t, time.Monday, biweek.Weekday())
+	assert.True(t, util.InTimeRange(now, biweek, util.PeriodBiweek.AddTo(biweek, 1)))
+	// Every day in the biweek should share the same start.
+	for day := biweek; day.Before(util.PeriodBiweek.AddTo(biweek, 1)); day = day.AddDate(0, 0, 1) {
+		assert.Equal(t, biweek, util.PeriodBiweek.Truncate(day))
+	}
+}
+
+func TestParseRelativeWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.Local)
+
+	period, start, end, err := util.ParseRelativeWindow("last-week", now)
+	require.NoError(t, err)
+	assert.Equal(t, util.PeriodWeek, period)
+	assert.Equal(t, time.Date(2026, time.October, 5, 0, 0, 0, 0, time.Local), start)
+	assert.Equal(t, time.Date(2026, time.October, 12, 0, 0, 0, 0, time.Local), end)
+
+	period, start, end, err = util.ParseRelativeWindow("this-month", now)
+	require.NoError(t, err)
+	assert.Equal(t, util.PeriodMonth, period)
+	assert.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local), start)
+	assert.Equal(t, now, end)
+
+	period, start, end, err = util.ParseRelativeWindow("last-quarter", now)
+	require.NoError(t, err)
+	assert.Equal(t, util.PeriodQuarter, period)
+	assert.Equal(t, time.Date(2026, time.July, 1, 0, 0, 0, 0, time.Local), start)
+	assert.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local), end)
+
+	period, start, end, err = util.ParseRelativeWindow("yesterday", now)
+	require.NoError(t, err)
+	assert.Equal(t, util.PeriodDay, period)
+	assert.Equal(t, time.Date(2026, time.October, 13, 0, 0, 0, 0, time.Local), start)
+	assert.Equal(t, time.Date(2026, time.October, 14, 0, 0, 0, 0, time.Local), end)
+
+	_, _, _, err = util.ParseRelativeWindow("week", now)
+	assert.Error(t, err)
+	_, _, _, err = util.ParseRelativeWindow("next-week", now)
+	assert.Error(t, err)
+}
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.Local)
+
+	got, err := util.ParseTime("2026-10-01 09:15", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, time.October, 1, 9, 15, 0, 0, time.Local), got)
+
+	got, err = util.ParseTime("2026-10-01", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local), got)
+
+	got, err = util.ParseTime("2w", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -14), got)
+
+	got, err = util.ParseTime("1mo", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, -1, 0), got)
+
+	_, err = util.ParseTime("last tuesday", now)
+	assert.Error(t, err)
+}